@@ -26,6 +26,81 @@ func addExamples(ps *param.PSet) error {
 			" order to update the modules."+
 			" The added feature is that you will only be shown"+
 			" the relevant subsection of modules.")
+	ps.AddExample(
+		"gomodlayers -filter-re 'github.com/acme/(auth|billing).*'"+
+			" -- */go.mod",
+		"This will show the modules whose names start with either"+
+			" github.com/acme/auth or github.com/acme/billing"+
+			" and any modules which depend on them recursively."+
+			"\n\n"+
+			"This can be useful when you want to see the modules"+
+			" owned by a particular team and their dependants.")
+	ps.AddExample(
+		"gomodlayers -why github.com/myname/app,github.com/myname/lib"+
+			" -- */go.mod",
+		"This will show every chain of requirements by which the"+
+			" app module comes to require the lib module."+
+			" Each step shows whether the requirement is direct or"+
+			" indirect and where in the go.mod file it is given.")
+	ps.AddExample(
+		"gomodlayers -package-report -pkg-sort-order 'lines-of-code|rev'"+
+			" -- */go.mod",
+		"This will print a report with one line per package"+
+			" rather than per module, with the largest packages first.")
+	ps.AddExample(
+		"gomodlayers -deprecated-report -- */go.mod",
+		"This will list every deprecated module together with"+
+			" its deprecation message and the modules which still"+
+			" require it. This can be used to find the modules"+
+			" which need to be migrated away from the deprecated ones.")
+	ps.AddExample(
+		"gomodlayers -markdown -show-cols level,name,used-by -- */go.mod",
+		"This will print the report as a Markdown table which can be"+
+			" pasted into a wiki page or a pull request comment.")
+	ps.AddExample(
+		"gomodlayers -html -- */go.mod > modules.html",
+		"This will write the report as a single HTML page which can"+
+			" be viewed in a browser. The modules can be sorted by"+
+			" any column and each module links to its details.")
+	ps.AddExample(
+		"gomodlayers -mermaid -strip-module-name-prefix github.com/myname/"+
+			" -- */go.mod",
+		"This will print a Mermaid graph of the modules, grouped by"+
+			" level, which can be put in a Markdown file on GitHub"+
+			" and will be drawn as a diagram.")
+	ps.AddExample(
+		"gomodlayers -graphml -- */go.mod > modules.graphml",
+		"This will write the module graph in the GraphML format"+
+			" which can be loaded into graph tools such as yEd"+
+			" for layout and clustering.")
+	ps.AddExample(
+		"gomodlayers -tree-root github.com/myname/app -- */go.mod",
+		"This will print the direct requirements of the app module"+
+			" as an indented tree, and the requirements of those"+
+			" modules and so on.")
+	ps.AddExample(
+		"gomodlayers -reverse-tree github.com/myname/lib -- */go.mod",
+		"This will print the modules which use the lib module"+
+			" as an indented tree, with the level of each module."+
+			" These are the modules which would need to be"+
+			" rebuilt after a change to the lib module.")
+	ps.AddExample(
+		"gomodlayers -sbom -- */go.mod > sbom.cdx.json",
+		"This will write a software bill of materials for the"+
+			" modules in the CycloneDX JSON format.")
+	ps.AddExample(
+		"gomodlayers -check-go-sum -show-cols name,missing-go-sum -- */go.mod",
+		"This will check the go.sum file of each module and report"+
+			" any requirements missing from the go.sum file and any"+
+			" entries that are no longer needed.")
+	ps.AddExample(
+		"gomodlayers -mod-cache -show-external -external-levels"+
+			" -show-cols level,name,cache-version,licence-file,module-size"+
+			" -- */go.mod",
+		"This will look up the external modules in the local module"+
+			" cache and show them in the report together with their"+
+			" versions, licence files and sizes. The levels of the"+
+			" modules take the external modules into account.")
 	ps.AddExample(
 		"gomodlayers -- dir1/go.mod dir2/go.mod dir3/go.mod",
 		"This will print the default output: an extensive introduction"+
@@ -14,22 +14,81 @@ import (
 )
 
 const (
-	paramHideHeader    = "hide-header"
-	paramHideIntro     = "hide-intro"
-	paramHideDupLevels = "hide-dup-levels"
-	paramBrief         = "brief"
-	paramHeaderRepeat  = "header-repeat"
-	paramSortOrder     = "sort-order"
-	paramShowCols      = "show-cols"
-	paramNamesByLevel  = "names-by-level"
-	paramNamesOnly     = "names-only"
-	paramFilter        = "filter"
-	paramPartialFilter = "partial-filter"
-	paramBackFilter    = "back-filter"
-	paramMakeDotFile   = "make-dot-file"
-	paramDotFileDir    = "dot-file-directory"
-	paramStripPrefix   = "strip-module-name-prefix"
-	paramHideModule    = "hide-module"
+	paramHideHeader       = "hide-header"
+	paramHideIntro        = "hide-intro"
+	paramHideDupLevels    = "hide-dup-levels"
+	paramBrief            = "brief"
+	paramHeaderRepeat     = "header-repeat"
+	paramSortOrder        = "sort-order"
+	paramShowCols         = "show-cols"
+	paramNamesByLevel     = "names-by-level"
+	paramNamesOnly        = "names-only"
+	paramFilter           = "filter"
+	paramPartialFilter    = "partial-filter"
+	paramBackFilter       = "back-filter"
+	paramMakeDotFile      = "make-dot-file"
+	paramDotFileDir       = "dot-file-directory"
+	paramStripPrefix      = "strip-module-name-prefix"
+	paramHideModule       = "hide-module"
+	paramStrictScan       = "strict-scan"
+	paramFilterRE         = "filter-re"
+	paramFilterGlob       = "filter-glob"
+	paramBackFilterRE     = "back-filter-re"
+	paramBackFilterGlob   = "back-filter-glob"
+	paramHideModRE        = "hide-module-re"
+	paramHideModGlob      = "hide-module-glob"
+	paramFilterDepth      = "filter-depth"
+	paramBackFilterDepth  = "back-filter-depth"
+	paramWhy              = "why"
+	paramWhyViaExternal   = "why-via-external"
+	paramWhyMaxPaths      = "why-max-paths"
+	paramShowPkgTests     = "show-package-tests"
+	paramPkgReport        = "package-report"
+	paramPkgShowCols      = "package-show-cols"
+	paramPkgSortOrder     = "package-sort-order"
+	paramIncludeGenerated = "include-generated-code"
+	paramGOOS             = "goos"
+	paramGOARCH           = "goarch"
+	paramBuildTags        = "build-tags"
+	paramAllPlatforms     = "all-platforms"
+	paramCheckGoVersions  = "check-go-versions"
+	paramCheckDirectives  = "check-directives"
+	paramCheckGoSum       = "check-go-sum"
+	paramModCache         = "mod-cache"
+	paramModCacheDir      = "mod-cache-dir"
+	paramShowExternal     = "show-external"
+	paramExternalLevels   = "external-levels"
+	paramDeprecatedReport = "deprecated-report"
+	paramMarkdown         = "markdown"
+	paramHTML             = "html"
+	paramMermaid          = "mermaid"
+	paramPlantUML         = "plantuml"
+	paramGraphML          = "graphml"
+	paramGEXF             = "gexf"
+	paramTree             = "tree"
+	paramTreeRoot         = "tree-root"
+	paramReverseTree      = "reverse-tree"
+	paramSBOM             = "sbom"
+	paramSBOMNoTimestamp  = "sbom-no-timestamp"
+)
+
+const (
+	reMatchNote = "\n\n" +
+		"The regular expression must match the whole of the" +
+		" module name, including any version suffix."
+	globMatchNote = "\n\n" +
+		"The pattern must match the whole of the module name," +
+		" including any version suffix." +
+		" Note that a '*' will not match a '/' so each" +
+		" part of the module name must be matched separately."
+	graphExportNote = "\n\n" +
+		"The nodes are the modules being shown and any external" +
+		" modules that they require. Each node gives the module's" +
+		" level, lines of code, number of packages, whether it is" +
+		" external and how many modules use it." +
+		" The edges are the requirements between the modules," +
+		" both direct and indirect, and give the kind of" +
+		" requirement and the version required."
 )
 
 type sortWay = rptmaker.SortWay
@@ -138,6 +197,83 @@ func addParams(prog *prog) param.PSetOptFunc {
 				}),
 		)
 
+		ps.Add(paramShowPkgTests, psetter.Bool{Value: &prog.showPkgTests},
+			"after the module report, print a second report"+
+				" listing every package in the modules shown"+
+				" with the lines of code and of test code"+
+				" and what tests it has."+
+				" This can be useful for finding untested code.",
+			param.AltNames("show-pkg-tests", "pkg-tests"),
+		)
+
+		ps.Add(paramPkgReport, psetter.Nil{},
+			"rather than the usual module report,"+
+				" print a report with one line per package"+
+				" in the modules shown."+
+				" The module filters are applied as for"+
+				" the module report and only the packages"+
+				" in the modules that would be shown are reported.",
+			param.AltNames("pkg-report", "packages"),
+			param.SeeAlso(paramPkgShowCols, paramPkgSortOrder),
+			param.PostAction(paction.SetVal(&prog.output, stylePkgs)),
+		)
+
+		ps.Add(paramDeprecatedReport, psetter.Nil{},
+			"rather than the usual module report,"+
+				" print a report listing every deprecated module"+
+				" in the collection together with its deprecation"+
+				" message and the modules which still require it."+
+				" Only those modules that would be shown"+
+				" are listed as requiring a deprecated module."+
+				" A module is deprecated if the module line in"+
+				" its go.mod file has a comment starting"+
+				" 'Deprecated:'.",
+			param.AltNames("deprecated"),
+			param.SeeAlso(string(ColDeprecated)),
+			param.PostAction(paction.SetVal(&prog.output, styleDeprec)),
+		)
+
+		ps.Add(paramPkgShowCols,
+			psetter.EnumList[rptmaker.ColID]{
+				Value: &prog.pkgColumnsToShow,
+				AllowedVals: psetter.AllowedVals[rptmaker.ColID](
+					prog.pkgCols.Reportable(),
+				),
+				Aliases: psetter.Aliases[rptmaker.ColID](
+					prog.pkgCols.ReportableAliases(),
+				),
+			},
+			"what columns should be shown in the package report.",
+			param.AltNames("pkg-show-cols", "pkg-cols", "pkg-col"),
+			param.SeeAlso(paramPkgReport, paramPkgSortOrder),
+		)
+
+		ps.Add(paramPkgSortOrder,
+			psetter.TaggedValueList[rptmaker.ColID, sortWay]{
+				Value: &prog.pkgSortBy,
+				AllowedVals: psetter.AllowedVals[rptmaker.ColID](
+					prog.pkgCols.Sortable(),
+				),
+				Aliases: psetter.Aliases[rptmaker.ColID](
+					prog.pkgCols.SortableAliases(),
+				),
+				TagAllowedVals: psetter.AllowedVals[sortWay](
+					rptmaker.AllowedSortDirections(),
+				),
+				TagAliases: psetter.Aliases[sortWay](
+					rptmaker.SortDirectionAliases(),
+				),
+				TagListSeparator: psetter.StrListSeparator{Sep: "|"},
+				TagChecks: []check.ValCk[[]sortWay]{
+					check.SliceLength[[]sortWay](check.ValBetween(0, 1)),
+				},
+			},
+			"what order should the packages be sorted"+
+				" in the package report",
+			param.AltNames("pkg-sort-by", "pkg-order-by", "pkg-order"),
+			param.SeeAlso(paramPkgReport, paramPkgShowCols),
+		)
+
 		ps.Add(paramNamesByLevel, psetter.Nil{},
 			"just show the module names in level order",
 			param.PostAction(paction.SetVal(&prog.showHeader, false)),
@@ -165,7 +301,48 @@ func addParams(prog *prog) param.PSetOptFunc {
 				" and module B uses A and C uses B but not A (directly)"+
 				" then modules A, B and C will be shown.",
 			param.AltNames("filt", "f"),
-			param.SeeAlso(paramPartialFilter, paramBackFilter),
+			param.SeeAlso(paramPartialFilter, paramBackFilter,
+				paramFilterRE, paramFilterGlob, paramFilterDepth),
+		)
+
+		ps.Add(paramFilterDepth,
+			psetter.Int[int]{
+				Value: &prog.filterDepth,
+				Checks: []check.ValCk[int]{
+					check.ValGE(0),
+				},
+			},
+			"the maximum number of steps away from the filtered"+
+				" modules that a module using them can be"+
+				" and still be shown."+
+				" A value of 0 will show just the filtered modules,"+
+				" 1 will also show the modules that use them directly"+
+				" and so on."+
+				" If this is not given there is no limit.",
+			param.AltNames("filt-depth"),
+			param.SeeAlso(paramFilter, paramBackFilterDepth),
+		)
+
+		ps.Add(paramFilterRE,
+			psetter.Map[string]{Value: &prog.modFilterRE},
+			"regular expressions matching the module names to filter by."+
+				" This behaves like the "+paramFilter+" parameter"+
+				" but any module whose name matches one of the"+
+				" regular expressions is used as a filter."+
+				reMatchNote,
+			param.AltNames("filt-re", "f-re"),
+			param.SeeAlso(paramFilter, paramFilterGlob),
+		)
+
+		ps.Add(paramFilterGlob,
+			psetter.Map[string]{Value: &prog.modFilterGlob},
+			"glob patterns matching the module names to filter by."+
+				" This behaves like the "+paramFilter+" parameter"+
+				" but any module whose name matches one of the"+
+				" patterns is used as a filter."+
+				globMatchNote,
+			param.AltNames("filt-glob", "f-glob"),
+			param.SeeAlso(paramFilter, paramFilterRE),
 		)
 
 		ps.Add(paramPartialFilter,
@@ -197,7 +374,48 @@ func addParams(prog *prog) param.PSetOptFunc {
 				" if the filter is on module A"+
 				" and module A uses B and B uses C"+
 				" then modules A, B and C will be shown.",
-			param.SeeAlso(paramPartialFilter, paramFilter),
+			param.SeeAlso(paramPartialFilter, paramFilter,
+				paramBackFilterRE, paramBackFilterGlob, paramBackFilterDepth),
+		)
+
+		ps.Add(paramBackFilterDepth,
+			psetter.Int[int]{
+				Value: &prog.backFilterDepth,
+				Checks: []check.ValCk[int]{
+					check.ValGE(0),
+				},
+			},
+			"the maximum number of steps away from the back-filtered"+
+				" modules that a module they use can be"+
+				" and still be shown."+
+				" A value of 0 will show just the back-filtered modules,"+
+				" 1 will also show the modules that they use directly"+
+				" and so on."+
+				" If this is not given there is no limit and any"+
+				" modules that they require indirectly"+
+				" will also be shown.",
+			param.SeeAlso(paramBackFilter, paramFilterDepth),
+		)
+
+		ps.Add(paramBackFilterRE,
+			psetter.Map[string]{Value: &prog.backFilterRE},
+			"regular expressions matching the module names to"+
+				" filter by."+
+				" This behaves like the "+paramBackFilter+" parameter"+
+				" but any module whose name matches one of the"+
+				" regular expressions is used as a filter."+
+				reMatchNote,
+			param.SeeAlso(paramBackFilter, paramBackFilterGlob),
+		)
+
+		ps.Add(paramBackFilterGlob,
+			psetter.Map[string]{Value: &prog.backFilterGlob},
+			"glob patterns matching the module names to filter by."+
+				" This behaves like the "+paramBackFilter+" parameter"+
+				" but any module whose name matches one of the"+
+				" patterns is used as a filter."+
+				globMatchNote,
+			param.SeeAlso(paramBackFilter, paramBackFilterRE),
 		)
 
 		ps.Add(paramHideModule,
@@ -205,6 +423,27 @@ func addParams(prog *prog) param.PSetOptFunc {
 			"the module names to hide."+
 				" The report will not show these modules.",
 			param.AltNames("hide-modules", "hide"),
+			param.SeeAlso(paramHideModRE, paramHideModGlob),
+		)
+
+		ps.Add(paramHideModRE,
+			psetter.Map[string]{Value: &prog.hideModRE},
+			"regular expressions matching the module names to hide."+
+				" The report will not show any module whose name"+
+				" matches one of the regular expressions."+
+				reMatchNote,
+			param.AltNames("hide-re"),
+			param.SeeAlso(paramHideModule, paramHideModGlob),
+		)
+
+		ps.Add(paramHideModGlob,
+			psetter.Map[string]{Value: &prog.hideModGlob},
+			"glob patterns matching the module names to hide."+
+				" The report will not show any module whose name"+
+				" matches one of the patterns."+
+				globMatchNote,
+			param.AltNames("hide-glob"),
+			param.SeeAlso(paramHideModule, paramHideModRE),
 		)
 
 		ps.Add(paramMakeDotFile,
@@ -217,6 +456,108 @@ func addParams(prog *prog) param.PSetOptFunc {
 			param.PostAction(paction.SetVal(&prog.output, styleDotFile)),
 		)
 
+		ps.Add(paramMarkdown,
+			psetter.Nil{},
+			"print the module report as a GitHub-flavoured"+
+				" Markdown table rather than as plain text."+
+				" Cells with several values have the values"+
+				" separated by HTML line breaks."+
+				" Unless it is suppressed, the table is preceded"+
+				" by the introductory text and a list"+
+				" describing each column."+
+				" This is suitable for wiki pages and"+
+				" pull request comments.",
+			param.AltNames("md"),
+			param.SeeAlso(paramHideIntro),
+			param.PostAction(paction.SetVal(&prog.output, styleMarkdown)),
+		)
+
+		ps.Add(paramHTML,
+			psetter.Nil{},
+			"print the module report as a single, self-contained"+
+				" HTML page rather than as plain text."+
+				" The page needs no network access to be viewed."+
+				" The modules are shown in a table grouped by level"+
+				" which can be sorted by clicking on the column headings."+
+				" Each module name links to the details of the module,"+
+				" giving the modules it uses and is used by and its"+
+				" packages. The package columns shown are those given"+
+				" by the "+paramPkgShowCols+" parameter.",
+			param.SeeAlso(paramMarkdown, paramPkgShowCols),
+			param.PostAction(paction.SetVal(&prog.output, styleHTML)),
+		)
+
+		ps.Add(paramMermaid,
+			psetter.Nil{},
+			"print a Mermaid graph showing the relationships"+
+				" between modules rather than the usual report."+
+				" The modules are grouped by level and the same"+
+				" modules and relationships are shown as in"+
+				" the Graphviz DOT file.",
+			param.SeeAlso(paramMakeDotFile, paramPlantUML),
+			param.PostAction(paction.SetVal(&prog.output, styleMermaid)),
+		)
+
+		ps.Add(paramPlantUML,
+			psetter.Nil{},
+			"print a PlantUML component diagram showing the"+
+				" relationships between modules rather than the"+
+				" usual report."+
+				" The modules are grouped by level and the same"+
+				" modules and relationships are shown as in"+
+				" the Graphviz DOT file.",
+			param.AltNames("puml"),
+			param.SeeAlso(paramMakeDotFile, paramMermaid),
+			param.PostAction(paction.SetVal(&prog.output, stylePlantUML)),
+		)
+
+		ps.Add(paramGraphML,
+			psetter.Nil{},
+			"print the module graph in the GraphML format,"+
+				" as used by graph tools such as yEd,"+
+				" rather than the usual report."+
+				graphExportNote,
+			param.SeeAlso(paramGEXF),
+			param.PostAction(paction.SetVal(&prog.output, styleGraphML)),
+		)
+
+		ps.Add(paramGEXF,
+			psetter.Nil{},
+			"print the module graph in the GEXF format,"+
+				" as used by graph tools such as Gephi,"+
+				" rather than the usual report."+
+				graphExportNote,
+			param.SeeAlso(paramGraphML),
+			param.PostAction(paction.SetVal(&prog.output, styleGEXF)),
+		)
+
+		ps.Add(paramSBOM,
+			psetter.Nil{},
+			"print a software bill of materials in the"+
+				" CycloneDX JSON format rather than the usual report."+
+				" The components are the modules being shown and"+
+				" any external modules that they require."+
+				" The version of each component is the highest"+
+				" version required by any module in the collection"+
+				" and the dependencies of each component are all"+
+				" its requirements, direct and indirect."+
+				" A module in the collection which no module"+
+				" requires is given the version "+develVersion+"."+
+				" This is generated from the go.mod files alone"+
+				" and needs no network access.",
+			param.AltNames("cyclonedx"),
+			param.SeeAlso(paramGraphML, paramSBOMNoTimestamp),
+			param.PostAction(paction.SetVal(&prog.output, styleSBOM)),
+		)
+
+		ps.Add(paramSBOMNoTimestamp,
+			psetter.Bool{Value: &prog.sbomNoTimestamp},
+			"leave the timestamp out of the software bill of materials"+
+				" so that the same modules always give the same output.",
+			param.AltNames("sbom-no-time"),
+			param.SeeAlso(paramSBOM),
+		)
+
 		ps.Add(paramDotFileDir,
 			psetter.Pathname{
 				Value:       &prog.dotFileDir,
@@ -243,6 +584,289 @@ func addParams(prog *prog) param.PSetOptFunc {
 			param.AltNames("strip-prefix"),
 		)
 
+		ps.Add(paramIncludeGenerated,
+			psetter.Bool{Value: &prog.scanOpts.includeGenerated},
+			"include generated Go files in the package statistics."+
+				" By default any file with a comment of the standard"+
+				" form, 'Code generated ... DO NOT EDIT.',"+
+				" before the package clause"+
+				" is excluded from the statistics so that"+
+				" they only reflect hand-written code."+
+				" The lines of generated code are always shown"+
+				" in the "+string(ColGeneratedLines)+" column.",
+			param.AltNames("include-generated", "incl-gen"),
+		)
+
+		ps.Add(paramCheckGoVersions,
+			psetter.Bool{Value: &prog.checkGoVersions},
+			"check that no module declares a higher go version"+
+				" than any of the modules which use it."+
+				" Such modules may stop their users from building"+
+				" once they are upgraded."+
+				" Any conflicts are reported on standard error"+
+				" and the program will exit with a non-zero status."+
+				" The conflicts for each module are also shown"+
+				" in the "+string(ColGoVerConflicts)+" column.",
+			param.AltNames("check-go-version"),
+		)
+
+		ps.Add(paramCheckDirectives,
+			psetter.Bool{Value: &prog.checkDirectives},
+			"check the retract and exclude directives in the go.mod"+
+				" files. A problem is reported if a module requires"+
+				" a version of another module in the collection"+
+				" which that module has retracted or if a module"+
+				" excludes a version of a module which another module"+
+				" in the collection requires."+
+				" Any problems are reported on standard error"+
+				" and the program will exit with a non-zero status.",
+			param.SeeAlso(string(ColRetracts), string(ColExcludes)),
+		)
+
+		ps.Add(paramCheckGoSum,
+			psetter.Bool{Value: &prog.checkGoSum},
+			"read the go.sum file alongside each go.mod file"+
+				" and check it against the requirements."+
+				" A problem is reported if a module requires"+
+				" a module for which its go.sum file is missing"+
+				" an entry or if the go.sum file has an entry for the"+
+				" contents of a module which it no longer requires."+
+				" For modules declaring go 1.17 or later"+
+				" the hash of the contents of every required module"+
+				" is needed, otherwise only the hash of its go.mod file."+
+				" Unneeded entries are only checked for modules"+
+				" declaring go 1.17 or later"+
+				" and entries for modules containing a required module"+
+				" are kept, as the go command uses them to check"+
+				" for ambiguous imports."+
+				" Any problems are reported on standard error"+
+				" and the program will exit with a non-zero status."+
+				" No network access is needed.",
+			param.AltNames("check-go-sums", "check-sums"),
+			param.SeeAlso(string(ColMissingSums), string(ColUnneededSums)),
+		)
+
+		ps.Add(paramModCache,
+			psetter.Bool{Value: &prog.useModCache},
+			"look up the external modules in the local module cache."+
+				" The go.mod file of each external module is read"+
+				" from the cache, for the highest version required"+
+				" by any module,"+
+				" to find its requirements and go version."+
+				" The modules it requires are then looked up in turn,"+
+				" and so on, until no new modules are found."+
+				" If a module found later requires a higher version"+
+				" of a module then that version is looked up instead."+
+				" If the source of the module is in the cache"+
+				" then its licence file and size are also found."+
+				" Requirements on modules in the collection"+
+				" are ignored."+
+				" The cache is never updated and"+
+				" no network access is needed."+
+				" Modules not in the cache are left unchanged.",
+			param.AltNames("use-mod-cache"),
+			param.SeeAlso(paramModCacheDir, paramShowExternal,
+				paramExternalLevels,
+				string(ColCacheVersion), string(ColLicenceFile),
+				string(ColModSize)),
+		)
+
+		ps.Add(paramModCacheDir,
+			psetter.Pathname{
+				Value:       &prog.modCacheDir,
+				Expectation: filecheck.DirExists(),
+			},
+			"the directory holding the module cache."+
+				" The default is taken from the GOMODCACHE"+
+				" environment variable or else from GOPATH,"+
+				" as the go command would.",
+			param.SeeAlso(paramModCache),
+		)
+
+		ps.Add(paramShowExternal,
+			psetter.Bool{Value: &prog.showExternal},
+			"include the external modules in the report."+
+				" By default only the modules in the collection"+
+				" are shown.",
+			param.AltNames("show-ext"),
+			param.SeeAlso(paramModCache),
+		)
+
+		ps.Add(paramExternalLevels,
+			psetter.Bool{Value: &prog.externalLevels},
+			"take the external modules into account when"+
+				" calculating the levels of the modules."+
+				" By default they are ignored and"+
+				" a module which uses no other module in the collection"+
+				" is at level 0."+
+				" If the external modules are looked up"+
+				" in the module cache then their levels"+
+				" are calculated from their requirements.",
+			param.AltNames("ext-levels"),
+			param.SeeAlso(paramModCache),
+		)
+
+		ps.Add(paramGOOS,
+			psetter.String[string]{Value: &prog.scanOpts.goos},
+			"the operating system to use when deciding"+
+				" whether a Go file would be included in a build."+
+				" The default is the value that the go command"+
+				" would use.",
+			param.SeeAlso(paramGOARCH, paramBuildTags, paramAllPlatforms),
+		)
+
+		ps.Add(paramGOARCH,
+			psetter.String[string]{Value: &prog.scanOpts.goarch},
+			"the architecture to use when deciding"+
+				" whether a Go file would be included in a build."+
+				" The default is the value that the go command"+
+				" would use.",
+			param.SeeAlso(paramGOOS, paramBuildTags, paramAllPlatforms),
+		)
+
+		ps.Add(paramBuildTags,
+			psetter.StrList[string]{Value: &prog.scanOpts.buildTags},
+			"the build tags to use when deciding"+
+				" whether a Go file would be included in a build."+
+				" Any file whose build constraints are not satisfied"+
+				" is excluded from the package statistics."+
+				" Note that files with a build constraint of"+
+				" 'ignore' will be excluded unless that tag is given.",
+			param.AltNames("tags"),
+			param.SeeAlso(paramGOOS, paramGOARCH, paramAllPlatforms,
+				string(ColExcludedFiles)),
+		)
+
+		ps.Add(paramAllPlatforms,
+			psetter.Bool{Value: &prog.scanOpts.allPlatforms},
+			"include any Go file that would be built for any"+
+				" operating system and architecture."+
+				" The "+paramGOOS+" and "+paramGOARCH+" values"+
+				" are ignored but the build tags are still used."+
+				" Note that this means that files for"+
+				" different platforms will all be counted.",
+			param.SeeAlso(paramGOOS, paramGOARCH, paramBuildTags),
+		)
+
+		ps.Add(paramStrictScan, psetter.Bool{Value: &prog.strictScan},
+			"treat any error found while scanning the packages in"+
+				" a module as fatal."+
+				" By default such errors are reported"+
+				" and the file in error is skipped but the"+
+				" report is still produced; the exit status"+
+				" is non-zero in either case."+
+				" The number of errors found in each module"+
+				" is shown in the "+string(ColScanErrors)+" column.",
+			param.AltNames("strict"),
+		)
+
+		ps.Add(paramWhy,
+			psetter.StrList[string]{
+				Value: &prog.whyMods,
+				Checks: []check.ValCk[[]string]{
+					check.SliceLength[[]string](check.ValEQ(2)),
+				},
+			},
+			"give the names of two modules, separated by a comma."+
+				" Rather than the usual report,"+
+				" every chain of requirements leading"+
+				" from the first module to the second will be shown."+
+				" Both direct and indirect requirements are followed"+
+				" and each requirement is shown with its version"+
+				" and the location in the go.mod file where"+
+				" it is given."+
+				"\n\n"+
+				"This can be useful when you want to know why"+
+				" a module appears in the results of a filter.",
+			param.SeeAlso(paramWhyViaExternal, paramWhyMaxPaths),
+			param.PostAction(
+				func(_ location.L, _ *param.BaseParam, _ []string) error {
+					prog.whyFrom, prog.whyTo = prog.whyMods[0], prog.whyMods[1]
+					prog.output = styleWhy
+
+					return nil
+				}),
+		)
+
+		ps.Add(paramTree, psetter.Nil{},
+			"rather than the usual report,"+
+				" print the direct requirements of each top-level"+
+				" module as an indented tree."+
+				" A top-level module is one which is not"+
+				" required by any of the other modules being shown."+
+				" A module whose requirements have already been"+
+				" shown is marked '(see above)' and its"+
+				" requirements are not shown again."+
+				" External modules are marked '(external)'"+
+				" and hidden modules are not shown."+
+				" If any filters or back filters are given then"+
+				" only the modules they select are shown.",
+			param.SeeAlso(paramTreeRoot),
+			param.PostAction(paction.SetVal(&prog.output, styleTree)),
+		)
+
+		ps.Add(paramTreeRoot,
+			psetter.StrList[string]{Value: &prog.treeRoots},
+			"give the names of the modules at the root of the"+
+				" requirement trees."+
+				" Rather than the usual report, the tree of"+
+				" the direct requirements of each of these modules"+
+				" will be shown.",
+			param.SeeAlso(paramTree, paramReverseTree),
+			param.PostAction(paction.SetVal(&prog.output, styleTree)),
+		)
+
+		ps.Add(paramReverseTree,
+			psetter.StrList[string]{Value: &prog.reverseTreeRoots},
+			"give the names of modules whose users are to be shown."+
+				" Rather than the usual report, the tree of the"+
+				" modules which directly require each of these modules"+
+				" will be shown, followed by the modules which require"+
+				" those and so on up to the top-level modules."+
+				" Each module is shown with its level."+
+				" A module whose users have already been"+
+				" shown is marked '(see above)' and its"+
+				" users are not shown again."+
+				" Hidden modules are not shown and, if any"+
+				" filters or back filters are given, nor are"+
+				" the modules which they do not select."+
+				"\n\n"+
+				"This shows the modules that would need to be"+
+				" rebuilt after a change to the given modules"+
+				" and the order in which to do it.",
+			param.AltNames("rev-tree"),
+			param.SeeAlso(paramTree, paramTreeRoot),
+			param.PostAction(paction.SetVal(&prog.output, styleRevTree)),
+		)
+
+		ps.Add(paramWhyViaExternal,
+			psetter.Bool{Value: &prog.whyViaExternal},
+			"allow the chains of requirements shown by the "+paramWhy+
+				" parameter to pass through modules which are not"+
+				" in the collection of modules being examined."+
+				" The requirements of such modules are only known"+
+				" if they are looked up in the module cache so"+
+				" this has no effect unless the "+paramModCache+
+				" parameter is also given.",
+			param.SeeAlso(paramWhy, paramModCache),
+		)
+
+		ps.Add(paramWhyMaxPaths,
+			psetter.Int[int]{
+				Value: &prog.whyMaxPaths,
+				Checks: []check.ValCk[int]{
+					check.ValGE(1),
+				},
+			},
+			"the maximum number of chains of requirements shown by the "+
+				paramWhy+" parameter."+
+				" The number of chains can grow very quickly"+
+				" as the number of modules grows.",
+			param.SeeAlso(paramWhy),
+		)
+
+		ps.AddFinalCheck(prog.compilePatterns)
+
 		ps.AddFinalCheck(func() error {
 			prog.moduleFiles = ps.TrailingParams()
 			if len(prog.moduleFiles) == 0 {
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"go/build"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// knownOS and knownArch give the GOOS and GOARCH values for which a file
+// is checked when scanning the packages for all platforms. These are the
+// values that go/build recognises in file name suffixes (taken from the
+// KnownOS and KnownArch lists in the Go source, internal/syslist) rather
+// than just the ports listed by 'go tool dist list'; otherwise a file for a
+// platform such as zos or sparc64 would never be included.
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd",
+		"illumos", "ios", "js", "linux", "nacl", "netbsd", "openbsd",
+		"plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be",
+		"loong64", "mips", "mipsle", "mips64", "mips64le", "mips64p32",
+		"mips64p32le", "ppc", "ppc64", "ppc64le", "riscv", "riscv64",
+		"s390", "s390x", "sparc", "sparc64", "wasm",
+	}
+)
+
+// buildContext returns a build context for the GOOS, GOARCH and build tags
+// given in the scan options. The context will read the file contents from
+// the supplied slice rather than from the file system. If the GOOS or
+// GOARCH differs from that of the host then cgo is only enabled if the
+// CGO_ENABLED environment variable is set to 1, as for the go command when
+// cross-compiling. When scanning for all platforms the caller sets
+// CgoEnabled for each platform tried.
+func (opts pkgScanOpts) buildContext(content []byte) build.Context {
+	ctxt := build.Default
+	ctxt.GOOS = opts.goos
+	ctxt.GOARCH = opts.goarch
+	ctxt.BuildTags = opts.buildTags
+	ctxt.OpenFile = func(_ string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	if opts.goos != build.Default.GOOS || opts.goarch != build.Default.GOARCH {
+		ctxt.CgoEnabled = os.Getenv("CGO_ENABLED") == "1"
+	}
+
+	return ctxt
+}
+
+// fileIsIncluded returns true if the file, with the given contents, would
+// be included in a build. This takes account of both any build constraints
+// in the file and any GOOS or GOARCH suffixes in the file name. If the scan
+// options specify all platforms then the file is included if it would be
+// included in a build for any of the known GOOS and GOARCH values, with cgo
+// either disabled or enabled.
+func (opts pkgScanOpts) fileIsIncluded(fName string, content []byte) (
+	bool, error,
+) {
+	dir, name := filepath.Split(fName)
+
+	if !opts.allPlatforms {
+		ctxt := opts.buildContext(content)
+		return ctxt.MatchFile(dir, name)
+	}
+
+	for _, goos := range knownOS {
+		for _, goarch := range knownArch {
+			platformOpts := opts
+			platformOpts.goos, platformOpts.goarch = goos, goarch
+
+			ctxt := platformOpts.buildContext(content)
+
+			for _, cgoEnabled := range []bool{false, true} {
+				ctxt.CgoEnabled = cgoEnabled
+
+				ok, err := ctxt.MatchFile(dir, name)
+				if ok || err != nil {
+					return ok, err
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
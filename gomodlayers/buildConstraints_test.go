@@ -0,0 +1,185 @@
+package main
+
+import (
+	"go/build"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFileIsIncluded(t *testing.T) {
+	t.Setenv("CGO_ENABLED", "")
+
+	const (
+		plain      = "package p\n"
+		linuxOnly  = "//go:build linux\n\npackage p\n"
+		notLinux   = "//go:build !linux\n\npackage p\n"
+		ignored    = "//go:build ignore\n\npackage p\n"
+		customTag  = "//go:build mytag\n\npackage p\n"
+		linuxArm64 = "//go:build linux && arm64\n\npackage p\n"
+		cgoOnly    = "//go:build cgo\n\npackage p\n"
+		notCgo     = "//go:build !cgo\n\npackage p\n"
+	)
+
+	linuxAmd64 := pkgScanOpts{goos: "linux", goarch: "amd64"}
+	withTag := linuxAmd64
+	withTag.buildTags = []string{"mytag"}
+	allPlatforms := linuxAmd64
+	allPlatforms.allPlatforms = true
+	host := pkgScanOpts{goos: build.Default.GOOS, goarch: build.Default.GOARCH}
+	crossOS := pkgScanOpts{goos: "plan9", goarch: build.Default.GOARCH}
+
+	testCases := []struct {
+		testhelper.ID
+		opts    pkgScanOpts
+		fName   string
+		content string
+		expVal  bool
+	}{
+		{
+			ID:      testhelper.MkID("no constraint"),
+			opts:    linuxAmd64,
+			fName:   "dir/f.go",
+			content: plain,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("matching constraint"),
+			opts:    linuxAmd64,
+			fName:   "dir/f.go",
+			content: linuxOnly,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("non-matching constraint"),
+			opts:    linuxAmd64,
+			fName:   "dir/f.go",
+			content: notLinux,
+			expVal:  false,
+		},
+		{
+			ID:      testhelper.MkID("ignore"),
+			opts:    allPlatforms,
+			fName:   "dir/f.go",
+			content: ignored,
+			expVal:  false,
+		},
+		{
+			ID:      testhelper.MkID("custom tag not given"),
+			opts:    linuxAmd64,
+			fName:   "dir/f.go",
+			content: customTag,
+			expVal:  false,
+		},
+		{
+			ID:      testhelper.MkID("custom tag given"),
+			opts:    withTag,
+			fName:   "dir/f.go",
+			content: customTag,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("GOOS file name suffix"),
+			opts:    linuxAmd64,
+			fName:   "dir/f_windows.go",
+			content: plain,
+			expVal:  false,
+		},
+		{
+			ID:      testhelper.MkID("GOARCH file name suffix"),
+			opts:    linuxAmd64,
+			fName:   "dir/f_linux_amd64.go",
+			content: plain,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("other arch"),
+			opts:    linuxAmd64,
+			fName:   "dir/f.go",
+			content: linuxArm64,
+			expVal:  false,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, other arch"),
+			opts:    allPlatforms,
+			fName:   "dir/f.go",
+			content: linuxArm64,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, GOOS file name suffix"),
+			opts:    allPlatforms,
+			fName:   "dir/f_windows.go",
+			content: notLinux,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, zos file name suffix"),
+			opts:    allPlatforms,
+			fName:   "dir/f_zos.go",
+			content: plain,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, sparc64 file name suffix"),
+			opts:    allPlatforms,
+			fName:   "dir/f_sparc64.go",
+			content: plain,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, hurd build constraint"),
+			opts:    allPlatforms,
+			fName:   "dir/f.go",
+			content: "//go:build hurd && mips64p32le\n\npackage p\n",
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, cgo"),
+			opts:    allPlatforms,
+			fName:   "dir/f.go",
+			content: cgoOnly,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("all platforms, not cgo"),
+			opts:    allPlatforms,
+			fName:   "dir/f.go",
+			content: notCgo,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("not cgo, cross-compiling"),
+			opts:    crossOS,
+			fName:   "dir/f.go",
+			content: notCgo,
+			expVal:  true,
+		},
+		{
+			ID:      testhelper.MkID("cgo, host platform"),
+			opts:    host,
+			fName:   "dir/f.go",
+			content: cgoOnly,
+			expVal:  build.Default.CgoEnabled,
+		},
+		{
+			ID:      testhelper.MkID("cgo, cross-compiling"),
+			opts:    crossOS,
+			fName:   "dir/f.go",
+			content: cgoOnly,
+			expVal:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		included, err := tc.opts.fileIsIncluded(tc.fName, []byte(tc.content))
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %s", err)
+
+			continue
+		}
+
+		testhelper.DiffBool(t, tc.IDStr(), "included", included, tc.expVal)
+	}
+}
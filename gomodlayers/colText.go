@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/nickwells/col.mod/v6/col"
+	"github.com/nickwells/col.mod/v6/rptmaker"
+)
+
+// noWrapWidth is the width used for the columns which wrap their values. It
+// is wider than any value is expected to be so that the values are not
+// wrapped; the table cells will be wrapped by whatever displays them.
+const noWrapWidth = 1000
+
+// colTexter gives the text of a single report column for a record. The
+// record is printed through a report with just that column and no header
+// and the padding is removed from the output. This means that the values
+// are formatted as in the standard columnar report except that they are
+// not wrapped.
+type colTexter[T any] struct {
+	buf *bytes.Buffer
+	rpt *rptmaker.Report[*prog, T]
+}
+
+// newColTexter returns a colTexter for the column
+func newColTexter[T any](
+	p *prog, cols *rptmaker.Cols[*prog, T], cid rptmaker.ColID,
+) (
+	colTexter[T], error,
+) {
+	ct := colTexter[T]{buf: &bytes.Buffer{}}
+
+	noWrap := *p
+	noWrap.maxNameLen = noWrapWidth
+
+	rpt, err := cols.MakeReport(&noWrap, ct.buf, []rptmaker.ColID{cid},
+		col.HdrOptDontPrint)
+	if err != nil {
+		return ct, err
+	}
+
+	ct.rpt = rpt
+
+	return ct, nil
+}
+
+// text returns the text of the column for the record. Values which are
+// printed over several lines are returned with the lines separated by
+// newlines.
+func (ct colTexter[T]) text(v T) (string, error) {
+	ct.buf.Reset()
+
+	if err := ct.rpt.PrintLine(v); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(ct.buf.String(), "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// newColTexters returns a colTexter for each of the columns
+func newColTexters[T any](
+	p *prog, cols *rptmaker.Cols[*prog, T], colIDs []rptmaker.ColID,
+) (
+	[]colTexter[T], error,
+) {
+	cts := make([]colTexter[T], 0, len(colIDs))
+
+	for _, cid := range colIDs {
+		ct, err := newColTexter(p, cols, cid)
+		if err != nil {
+			return nil, err
+		}
+
+		cts = append(cts, ct)
+	}
+
+	return cts, nil
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"cmp"
 	"errors"
+	"go/version"
 	"strings"
 
 	"github.com/nickwells/col.mod/v6/col"
 	"github.com/nickwells/col.mod/v6/colfmt"
 	"github.com/nickwells/col.mod/v6/rptmaker"
+	"golang.org/x/mod/semver"
 )
 
 // these constants name the available columns
@@ -23,16 +26,79 @@ const (
 	ColUses           = rptmaker.ColID("uses")
 	ColPackages       = rptmaker.ColID("packages")
 	ColPkgLines       = rptmaker.ColID("lines-of-code")
+	ColScanErrors     = rptmaker.ColID("scan-errors")
+	ColBlastLoC       = rptmaker.ColID("blast-radius-loc")
+	ColBlastPkgs      = rptmaker.ColID("blast-radius-packages")
+	ColAfferent       = rptmaker.ColID("afferent-coupling")
+	ColEfferent       = rptmaker.ColID("efferent-coupling")
+	ColInstability    = rptmaker.ColID("instability")
+	ColAbstractness   = rptmaker.ColID("abstractness")
+	ColMainSeqDist    = rptmaker.ColID("main-seq-distance")
+	ColTestLines      = rptmaker.ColID("test-lines-of-code")
+	ColTestRatio      = rptmaker.ColID("test-code-ratio")
+	ColUntestedPkgs   = rptmaker.ColID("untested-packages")
+	ColCommands       = rptmaker.ColID("commands")
+	ColLibraries      = rptmaker.ColID("libraries")
+	ColCommandNames   = rptmaker.ColID("command-names")
+	ColAPITypes       = rptmaker.ColID("exported-types")
+	ColAPIFuncs       = rptmaker.ColID("exported-funcs")
+	ColAPIMethods     = rptmaker.ColID("exported-methods")
+	ColAPIConsts      = rptmaker.ColID("exported-consts")
+	ColAPIVars        = rptmaker.ColID("exported-vars")
+	ColAPITotal       = rptmaker.ColID("api-size")
+	ColCodeLines      = rptmaker.ColID("code-lines")
+	ColCommentLines   = rptmaker.ColID("comment-lines")
+	ColBlankLines     = rptmaker.ColID("blank-lines")
+	ColGeneratedLines = rptmaker.ColID("generated-lines-of-code")
+	ColExcludedCount  = rptmaker.ColID("excluded-file-count")
+	ColExcludedFiles  = rptmaker.ColID("excluded-files")
+	ColGoVersion      = rptmaker.ColID("go-version")
+	ColToolchain      = rptmaker.ColID("toolchain")
+	ColGoVerConflicts = rptmaker.ColID("go-version-conflicts")
+	ColRetracts       = rptmaker.ColID("retracted-versions")
+	ColExcludes       = rptmaker.ColID("excluded-versions")
+	ColTools          = rptmaker.ColID("tools")
+	ColGodebugs       = rptmaker.ColID("godebug")
+	ColDeprecated     = rptmaker.ColID("deprecated")
+	ColMissingSums    = rptmaker.ColID("missing-go-sum")
+	ColUnneededSums   = rptmaker.ColID("unneeded-go-sum")
+	ColCacheVersion   = rptmaker.ColID("cache-version")
+	ColLicenceFile    = rptmaker.ColID("licence-file")
+	ColModSize        = rptmaker.ColID("module-size")
 
-	AliasLines  = rptmaker.ColID("lines")
-	AliasLoC    = rptmaker.ColID("loc")
-	AliasFull   = rptmaker.ColID("full")
-	AliasDirect = rptmaker.ColID("direct")
+	AliasLines   = rptmaker.ColID("lines")
+	AliasLoC     = rptmaker.ColID("loc")
+	AliasFull    = rptmaker.ColID("full")
+	AliasDirect  = rptmaker.ColID("direct")
+	AliasBlast   = rptmaker.ColID("blast-radius")
+	AliasCa      = rptmaker.ColID("ca")
+	AliasCe      = rptmaker.ColID("ce")
+	AliasStab    = rptmaker.ColID("stability")
+	AliasTestLoC = rptmaker.ColID("test-loc")
+	AliasTests   = rptmaker.ColID("tests")
+	AliasAPI     = rptmaker.ColID("api")
+	AliasSize    = rptmaker.ColID("size")
+	AliasGenLoC  = rptmaker.ColID("generated-loc")
 
 	indirectSeparator = "** Indirect **"
 	externalSeparator = "** External **"
 
 	separatorCount = 2 // the blank line plus the separator itself
+
+	buildConstraintNote = " A file is excluded if its build constraints" +
+		" or any GOOS or GOARCH suffix on its name" +
+		" are not satisfied by the GOOS, GOARCH and build tags" +
+		" being used."
+
+	goVersionWidth = 10 // enough to show a version such as 1.22.10
+
+	modVersionWidth = 34 // enough to show a pseudo-version
+	licenceWidth    = 16
+	modSizeWidth    = 10
+
+	metricWidth = 4 // enough to show a value between 0 and 1
+	metricPrec  = 2
+	ratioWidth  = 6
 )
 
 // addColLevel adds the level column to the supplied cols parameter.
@@ -363,7 +429,9 @@ func addColPackages(cols *rptmaker.Cols[*prog, *modInfo]) error {
 	return cols.Add(ColPackages,
 		rptmaker.NewColInfo(
 			"this gives the number of packages that are in this"+
-				" module. It will include commands (with package name 'main').",
+				" module. It will include commands (with package name 'main')."+
+				" The "+string(ColCommands)+" and "+string(ColLibraries)+
+				" columns show these separately.",
 			[]string{"Package", "Count"},
 			// mkCol
 			func(prog *prog, headings []string) *col.Col {
@@ -383,7 +451,11 @@ func addColPkgLines(cols *rptmaker.Cols[*prog, *modInfo]) error {
 	return cols.Add(ColPkgLines,
 		rptmaker.NewColInfo(
 			"this gives the total number of lines of non-test code"+
-				" in the packages.",
+				" in the packages."+
+				" This includes comments and blank lines, the "+
+				string(ColCodeLines)+", "+string(ColCommentLines)+
+				" and "+string(ColBlankLines)+
+				" columns show these separately.",
 			[]string{"Package", "LoC"},
 			// mkCol
 			func(prog *prog, headings []string) *col.Col {
@@ -400,6 +472,833 @@ func addColPkgLines(cols *rptmaker.Cols[*prog, *modInfo]) error {
 		))
 }
 
+// addColScanErrors adds the scanErrors column to the supplied cols
+// parameter.
+func addColScanErrors(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColScanErrors,
+		rptmaker.NewColInfo(
+			"this gives the number of errors found while scanning the"+
+				" packages in this module. Any Go file that could not be"+
+				" parsed will not be included in the package"+
+				" statistics so a non-zero value means that those"+
+				" statistics are incomplete.",
+			[]string{"Scan", "Errors"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return len(mi.ScanErrors) },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return len(a.ScanErrors) - len(b.ScanErrors)
+			},
+		))
+}
+
+// addColBlastLoC adds the blastLoC column to the supplied cols parameter.
+func addColBlastLoC(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColBlastLoC,
+		rptmaker.NewColInfo(
+			"this gives the total number of lines of non-test code"+
+				" in all the modules in the collection that use this"+
+				" module, either directly or indirectly."+
+				" A module used by a few large modules may have"+
+				" a greater impact than one used by many small ones"+
+				" and so this can be a better guide than the use count"+
+				" to the impact of a change to this module.",
+			[]string{"Blast", "Radius", "(LoC)"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.DependantsLoC },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.DependantsLoC - b.DependantsLoC
+			},
+		))
+}
+
+// addColBlastPkgs adds the blastPkgs column to the supplied cols parameter.
+func addColBlastPkgs(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColBlastPkgs,
+		rptmaker.NewColInfo(
+			"this gives the total number of packages"+
+				" in all the modules in the collection that use this"+
+				" module, either directly or indirectly.",
+			[]string{"Blast", "Radius", "(Pkgs)"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.DependantsPkgs },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.DependantsPkgs - b.DependantsPkgs
+			},
+		))
+}
+
+// addColAfferent adds the afferent coupling column to the supplied cols
+// parameter.
+func addColAfferent(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColAfferent,
+		rptmaker.NewColInfo(
+			"this gives the afferent coupling (Ca) of the module."+
+				" This is the number of modules in the collection"+
+				" that use this module directly.",
+			[]string{"Ca"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.afferentCoupling() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.afferentCoupling() - b.afferentCoupling()
+			},
+		))
+}
+
+// addColEfferent adds the efferent coupling column to the supplied cols
+// parameter.
+func addColEfferent(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColEfferent,
+		rptmaker.NewColInfo(
+			"this gives the efferent coupling (Ce) of the module."+
+				" This is the number of modules, whether in the"+
+				" collection or not, that this module uses directly.",
+			[]string{"Ce"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.efferentCoupling() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.efferentCoupling() - b.efferentCoupling()
+			},
+		))
+}
+
+// addColInstability adds the instability column to the supplied cols
+// parameter.
+func addColInstability(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColInstability,
+		rptmaker.NewColInfo(
+			"this gives the instability (I) of the module."+
+				" This is the ratio of the efferent coupling"+
+				" to the total coupling: I = Ce/(Ca+Ce)."+
+				" A value of 0 indicates a module which is used"+
+				" by others but uses nothing itself and so"+
+				" is maximally stable."+
+				" A value of 1 indicates a module which uses others"+
+				" but is not used by any and so is free to change.",
+			[]string{"I"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Float{W: metricWidth, Prec: metricPrec},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.instability() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmp.Compare(a.instability(), b.instability())
+			},
+		))
+}
+
+// addColAbstractness adds the abstractness column to the supplied cols
+// parameter.
+func addColAbstractness(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColAbstractness,
+		rptmaker.NewColInfo(
+			"this gives the abstractness (A) of the module."+
+				" This is the proportion of the exported types"+
+				" in the non-test code of the module"+
+				" which are interfaces.",
+			[]string{"A"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Float{W: metricWidth, Prec: metricPrec},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.abstractness() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmp.Compare(a.abstractness(), b.abstractness())
+			},
+		))
+}
+
+// addColMainSeqDist adds the distance from the main sequence column to the
+// supplied cols parameter.
+func addColMainSeqDist(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColMainSeqDist,
+		rptmaker.NewColInfo(
+			"this gives the distance (D) of the module from"+
+				" the main sequence: D = |A + I - 1|."+
+				" A value near 0 indicates a module which balances"+
+				" abstractness against stability."+
+				" A value near 1 indicates a module which is either"+
+				" stable and concrete and so hard to change"+
+				" or else unstable and abstract and so of little use.",
+			[]string{"D"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Float{W: metricWidth, Prec: metricPrec},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.mainSeqDistance() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmp.Compare(a.mainSeqDistance(), b.mainSeqDistance())
+			},
+		))
+}
+
+// addColTestLines adds the testLines column to the supplied cols parameter.
+func addColTestLines(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColTestLines,
+		rptmaker.NewColInfo(
+			"this gives the total number of lines of test code"+
+				" in the packages.",
+			[]string{"Test", "LoC"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.TestLinesOfCode },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.TestLinesOfCode - b.TestLinesOfCode
+			},
+		))
+}
+
+// addColTestRatio adds the testRatio column to the supplied cols parameter.
+func addColTestRatio(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColTestRatio,
+		rptmaker.NewColInfo(
+			"this gives the ratio of the lines of test code"+
+				" to the lines of non-test code in the packages."+
+				" A module with no non-test code will show a ratio of 0.",
+			[]string{"Test", "Ratio"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Float{W: ratioWidth, Prec: metricPrec},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.testRatio() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmp.Compare(a.testRatio(), b.testRatio())
+			},
+		))
+}
+
+// addColUntestedPkgs adds the untestedPkgs column to the supplied cols
+// parameter.
+func addColUntestedPkgs(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColUntestedPkgs,
+		rptmaker.NewColInfo(
+			"this gives the number of packages in this module"+
+				" which have no tests at all.",
+			[]string{"Untested", "Packages"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.untestedPkgCount() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.untestedPkgCount() - b.untestedPkgCount()
+			},
+		))
+}
+
+// addColCommands adds the commands column to the supplied cols parameter.
+func addColCommands(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColCommands,
+		rptmaker.NewColInfo(
+			"this gives the number of packages in this module"+
+				" which are commands (with package name 'main')."+
+				" A module with commands ships programs and so"+
+				" is likely to need a release process.",
+			[]string{"Command", "Count"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.commandCount() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.commandCount() - b.commandCount()
+			},
+		))
+}
+
+// addColLibraries adds the libraries column to the supplied cols parameter.
+func addColLibraries(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColLibraries,
+		rptmaker.NewColInfo(
+			"this gives the number of packages in this module"+
+				" which are libraries rather than commands.",
+			[]string{"Library", "Count"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.libraryCount() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.libraryCount() - b.libraryCount()
+			},
+		))
+}
+
+// addColCommandNames adds the commandNames column to the supplied cols
+// parameter.
+func addColCommandNames(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColCommandNames,
+		rptmaker.NewColInfo(
+			"this lists the names of the commands in this module."+
+				" This is the name that the command would be"+
+				" installed as.",
+			[]string{"Commands"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any {
+				return strings.Join(mi.commandNames(), "\n")
+			},
+			nil))
+}
+
+// lineColDescs gives the descriptions and headings of the line count
+// columns. The same columns are available in both the module and the
+// package reports.
+var lineColDescs = []struct {
+	id       rptmaker.ColID
+	desc     string
+	headings []string
+	count    func(LineCounts) int
+}{
+	{
+		id: ColCodeLines,
+		desc: "this gives the number of lines containing code," +
+			" including those that also have a comment",
+		headings: []string{"Code", "Lines"},
+		count:    func(lc LineCounts) int { return lc.Code },
+	},
+	{
+		id: ColCommentLines,
+		desc: "this gives the number of lines containing only" +
+			" comments",
+		headings: []string{"Comment", "Lines"},
+		count:    func(lc LineCounts) int { return lc.Comment },
+	},
+	{
+		id:       ColBlankLines,
+		desc:     "this gives the number of blank lines",
+		headings: []string{"Blank", "Lines"},
+		count:    func(lc LineCounts) int { return lc.Blank },
+	},
+}
+
+// apiColDescs gives the descriptions and headings of the exported API
+// columns. The same columns are available in both the module and the
+// package reports.
+var apiColDescs = []struct {
+	id       rptmaker.ColID
+	desc     string
+	note     string
+	headings []string
+	count    func(APICounts) int
+}{
+	{
+		id:       ColAPITypes,
+		desc:     "this gives the number of exported types",
+		headings: []string{"Exported", "Types"},
+		count:    func(ac APICounts) int { return ac.Types },
+	},
+	{
+		id:       ColAPIFuncs,
+		desc:     "this gives the number of exported functions",
+		headings: []string{"Exported", "Funcs"},
+		count:    func(ac APICounts) int { return ac.Funcs },
+	},
+	{
+		id: ColAPIMethods,
+		desc: "this gives the number of exported methods" +
+			" of exported types",
+		headings: []string{"Exported", "Methods"},
+		count:    func(ac APICounts) int { return ac.Methods },
+	},
+	{
+		id:       ColAPIConsts,
+		desc:     "this gives the number of exported constants",
+		headings: []string{"Exported", "Consts"},
+		count:    func(ac APICounts) int { return ac.Consts },
+	},
+	{
+		id:       ColAPIVars,
+		desc:     "this gives the number of exported variables",
+		headings: []string{"Exported", "Vars"},
+		count:    func(ac APICounts) int { return ac.Vars },
+	},
+	{
+		id: ColAPITotal,
+		desc: "this gives the total number of exported types," +
+			" functions, methods, constants and variables",
+		note: " The larger the exported API, the greater the risk" +
+			" that a change will break the code that uses it.",
+		headings: []string{"API", "Size"},
+		count:    func(ac APICounts) int { return ac.total() },
+	},
+}
+
+// addColsAPI adds the exported API columns to the supplied cols parameter.
+func addColsAPI(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	allErrs := []error{}
+
+	for _, acd := range apiColDescs {
+		allErrs = append(allErrs, cols.Add(acd.id,
+			rptmaker.NewColInfo(
+				acd.desc+" in the non-test code of all the packages"+
+					" in this module which other modules can import."+
+					" Commands and internal packages are not"+
+					" counted."+acd.note,
+				acd.headings,
+				// mkCol
+				func(prog *prog, headings []string) *col.Col {
+					return col.New(&colfmt.Int{W: prog.reportDigits},
+						headings...)
+				},
+				// colVal
+				func(mi *modInfo) any { return acd.count(mi.API) },
+				// cmpVals
+				func(a, b *modInfo) int {
+					return acd.count(a.API) - acd.count(b.API)
+				},
+			)))
+	}
+
+	return errors.Join(allErrs...)
+}
+
+// addColsLines adds the line count columns to the supplied cols parameter.
+func addColsLines(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	allErrs := []error{}
+
+	for _, lcd := range lineColDescs {
+		allErrs = append(allErrs, cols.Add(lcd.id,
+			rptmaker.NewColInfo(
+				lcd.desc+" in the non-test code of all the packages"+
+					" in this module.",
+				lcd.headings,
+				// mkCol
+				func(prog *prog, headings []string) *col.Col {
+					return col.New(&colfmt.Int{W: prog.reportDigits},
+						headings...)
+				},
+				// colVal
+				func(mi *modInfo) any { return lcd.count(mi.Lines) },
+				// cmpVals
+				func(a, b *modInfo) int {
+					return lcd.count(a.Lines) - lcd.count(b.Lines)
+				},
+			)))
+	}
+
+	return errors.Join(allErrs...)
+}
+
+// addColGeneratedLines adds the generatedLines column to the supplied cols
+// parameter.
+func addColGeneratedLines(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColGeneratedLines,
+		rptmaker.NewColInfo(
+			"this gives the total number of lines of generated code"+
+				" in the packages, including any generated test code."+
+				" A file is taken to be generated if it has a"+
+				" comment of the standard form,"+
+				" 'Code generated ... DO NOT EDIT.',"+
+				" before the package clause."+
+				" Unless the "+paramIncludeGenerated+" parameter is given"+
+				" generated files are not included in any of the"+
+				" other statistics.",
+			[]string{"Generated", "LoC"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.GeneratedLoC },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return a.GeneratedLoC - b.GeneratedLoC
+			},
+		))
+}
+
+// addColExcludedCount adds the excludedCount column to the supplied cols
+// parameter.
+func addColExcludedCount(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColExcludedCount,
+		rptmaker.NewColInfo(
+			"this gives the number of Go files in the packages"+
+				" which have been excluded from the statistics"+
+				" because they would not be included in a build."+
+				buildConstraintNote,
+			[]string{"Excluded", "Files"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return len(mi.ExcludedFiles) },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return len(a.ExcludedFiles) - len(b.ExcludedFiles)
+			},
+		))
+}
+
+// addColExcludedFiles adds the excludedFiles column to the supplied cols
+// parameter.
+func addColExcludedFiles(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColExcludedFiles,
+		rptmaker.NewColInfo(
+			"this lists the Go files in the packages"+
+				" which have been excluded from the statistics"+
+				" because they would not be included in a build."+
+				" The names are given relative to the module directory."+
+				buildConstraintNote,
+			[]string{"Excluded Files"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any {
+				return strings.Join(mi.ExcludedFiles, "\n")
+			},
+			nil))
+}
+
+// addColGoVersion adds the goVersion column to the supplied cols parameter.
+func addColGoVersion(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColGoVersion,
+		rptmaker.NewColInfo(
+			"this gives the go version given in the go directive"+
+				" of the module's go.mod file."+
+				" It is blank if there is no go directive.",
+			[]string{"Go", "Version"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: goVersionWidth}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.GoVersion },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return version.Compare(a.goVersionOf(), b.goVersionOf())
+			},
+		))
+}
+
+// addColToolchain adds the toolchain column to the supplied cols parameter.
+func addColToolchain(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColToolchain,
+		rptmaker.NewColInfo(
+			"this gives the toolchain given in the toolchain directive"+
+				" of the module's go.mod file."+
+				" It is blank if there is no toolchain directive.",
+			[]string{"Toolchain"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: goVersionWidth}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.Toolchain },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return version.Compare(a.Toolchain, b.Toolchain)
+			},
+		))
+}
+
+// addColGoVerConflicts adds the goVerConflicts column to the supplied cols
+// parameter.
+func addColGoVerConflicts(p *prog, cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColGoVerConflicts,
+		rptmaker.NewColInfo(
+			"this lists the modules using this module, directly or"+
+				" indirectly, which declare a lower go version than"+
+				" this module does."+
+				" Such modules may no longer build once they are"+
+				" upgraded to use this module."+
+				" Modules without a go directive are not checked.",
+			[]string{"Go Version Conflicts"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any {
+				return strings.Join(mi.goVersionConflictNames(p.stripPrefix),
+					"\n")
+			},
+			// cmpVals
+			func(a, b *modInfo) int {
+				return len(a.goVersionConflicts()) -
+					len(b.goVersionConflicts())
+			},
+		))
+}
+
+// addColDeprecated adds the deprecated column to the supplied cols
+// parameter.
+func addColDeprecated(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColDeprecated,
+		rptmaker.NewColInfo(
+			"this gives the deprecation message for the module."+
+				" This is taken from any comment starting"+
+				" 'Deprecated:' on the module line of the"+
+				" module's go.mod file."+
+				" It is blank if the module is not deprecated.",
+			[]string{"Deprecated"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.Deprecated },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmpBool(a.Deprecated != "", b.Deprecated != "")
+			},
+		))
+}
+
+// addColMissingSums adds the missingSums column to the supplied cols
+// parameter.
+func addColMissingSums(p *prog, cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColMissingSums,
+		rptmaker.NewColInfo(
+			"this lists the modules required by this module"+
+				" for which the module's go.sum file is missing"+
+				" an entry."+
+				" This is only shown if the go.sum files"+
+				" are checked.",
+			[]string{"Missing", "go.sum Entries"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any {
+				return strings.Join(mi.missingSumNames(p.stripPrefix), "\n")
+			},
+			// cmpVals
+			func(a, b *modInfo) int {
+				return len(a.missingSums()) - len(b.missingSums())
+			},
+		))
+}
+
+// addColUnneededSums adds the unneededSums column to the supplied cols
+// parameter.
+func addColUnneededSums(p *prog, cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColUnneededSums,
+		rptmaker.NewColInfo(
+			"this lists the module versions whose contents have"+
+				" an entry in this module's go.sum file but which"+
+				" this module does not require, either directly or"+
+				" as a module containing a required module."+
+				" This is only shown if the go.sum files"+
+				" are checked.",
+			[]string{"Unneeded", "go.sum Entries"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.WrappedString{W: prog.maxNameLen},
+					headings...)
+			},
+			// colVal
+			func(mi *modInfo) any {
+				return strings.Join(mi.unneededSumNames(p.stripPrefix), "\n")
+			},
+			// cmpVals
+			func(a, b *modInfo) int {
+				return len(a.UnneededSums) - len(b.UnneededSums)
+			},
+		))
+}
+
+// addColCacheVersion adds the cacheVersion column to the supplied cols
+// parameter.
+func addColCacheVersion(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColCacheVersion,
+		rptmaker.NewColInfo(
+			"this gives the version of an external module"+
+				" which was found in the module cache."+
+				" It is blank for modules in the collection"+
+				" and for external modules which"+
+				" were not found.",
+			[]string{"Cache", "Version"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: modVersionWidth}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.cacheVersion() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return semver.Compare(a.cacheVersion(), b.cacheVersion())
+			},
+		))
+}
+
+// addColLicenceFile adds the licenceFile column to the supplied cols
+// parameter.
+func addColLicenceFile(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColLicenceFile,
+		rptmaker.NewColInfo(
+			"this gives the name of the licence file of an external"+
+				" module whose source was found in the module cache."+
+				" It is blank if no licence file was found.",
+			[]string{"Licence", "File"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: licenceWidth}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.licenceFile() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return strings.Compare(a.licenceFile(), b.licenceFile())
+			},
+		))
+}
+
+// addColModSize adds the modSize column to the supplied cols parameter.
+func addColModSize(cols *rptmaker.Cols[*prog, *modInfo]) error {
+	return cols.Add(ColModSize,
+		rptmaker.NewColInfo(
+			"this gives the total size, in bytes, of the files of an"+
+				" external module whose source was found in the"+
+				" module cache."+
+				" It is zero for any other module.",
+			[]string{"Module", "Size"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: modSizeWidth}, headings...)
+			},
+			// colVal
+			func(mi *modInfo) any { return mi.cacheSize() },
+			// cmpVals
+			func(a, b *modInfo) int {
+				return cmp.Compare(a.cacheSize(), b.cacheSize())
+			},
+		))
+}
+
+// directiveColDescs gives the descriptions and headings of the columns
+// showing the entries from the go.mod file directives
+var directiveColDescs = []struct {
+	id       rptmaker.ColID
+	desc     string
+	headings []string
+	entries  func(*prog, *modInfo) []string
+}{
+	{
+		id: ColRetracts,
+		desc: "this lists the versions of this module which it has" +
+			" retracted, together with any reason given.",
+		headings: []string{"Retracted", "Versions"},
+		entries: func(_ *prog, mi *modInfo) []string {
+			return mi.retractDescs()
+		},
+	},
+	{
+		id: ColExcludes,
+		desc: "this lists the module versions which this module" +
+			" excludes.",
+		headings: []string{"Excluded", "Versions"},
+		entries: func(p *prog, mi *modInfo) []string {
+			return mi.excludeDescs(p.stripPrefix)
+		},
+	},
+	{
+		id:       ColTools,
+		desc:     "this lists the tools which this module gives.",
+		headings: []string{"Tools"},
+		entries: func(_ *prog, mi *modInfo) []string {
+			return mi.toolNames()
+		},
+	},
+	{
+		id: ColGodebugs,
+		desc: "this lists the godebug settings which this module" +
+			" gives.",
+		headings: []string{"Godebug", "Settings"},
+		entries: func(_ *prog, mi *modInfo) []string {
+			return mi.godebugSettings()
+		},
+	},
+}
+
+// addColsDirectives adds the directive columns to the supplied cols
+// parameter.
+func addColsDirectives(p *prog, cols *rptmaker.Cols[*prog, *modInfo]) error {
+	allErrs := []error{}
+
+	for _, dcd := range directiveColDescs {
+		allErrs = append(allErrs, cols.Add(dcd.id,
+			rptmaker.NewColInfo(
+				dcd.desc+" These are taken from the module's go.mod file.",
+				dcd.headings,
+				// mkCol
+				func(prog *prog, headings []string) *col.Col {
+					return col.New(
+						&colfmt.WrappedString{W: prog.maxNameLen},
+						headings...)
+				},
+				// colVal
+				func(mi *modInfo) any {
+					return strings.Join(dcd.entries(p, mi), "\n")
+				},
+				// cmpVals
+				func(a, b *modInfo) int {
+					return len(dcd.entries(p, a)) - len(dcd.entries(p, b))
+				},
+			)))
+	}
+
+	return errors.Join(allErrs...)
+}
+
 // populateCols populates and returns the report columns
 func (p *prog) populateCols() *rptmaker.Cols[*prog, *modInfo] {
 	allErrs := []error{}
@@ -417,9 +1316,85 @@ func (p *prog) populateCols() *rptmaker.Cols[*prog, *modInfo] {
 	allErrs = append(allErrs, addColUses(p, cols))
 	allErrs = append(allErrs, addColPackages(cols))
 	allErrs = append(allErrs, addColPkgLines(cols))
+	allErrs = append(allErrs, addColScanErrors(cols))
+	allErrs = append(allErrs, addColBlastLoC(cols))
+	allErrs = append(allErrs, addColBlastPkgs(cols))
+	allErrs = append(allErrs, addColAfferent(cols))
+	allErrs = append(allErrs, addColEfferent(cols))
+	allErrs = append(allErrs, addColInstability(cols))
+	allErrs = append(allErrs, addColAbstractness(cols))
+	allErrs = append(allErrs, addColMainSeqDist(cols))
+	allErrs = append(allErrs, addColTestLines(cols))
+	allErrs = append(allErrs, addColTestRatio(cols))
+	allErrs = append(allErrs, addColUntestedPkgs(cols))
+	allErrs = append(allErrs, addColCommands(cols))
+	allErrs = append(allErrs, addColLibraries(cols))
+	allErrs = append(allErrs, addColCommandNames(cols))
+	allErrs = append(allErrs, addColsAPI(cols))
+	allErrs = append(allErrs, addColsLines(cols))
+	allErrs = append(allErrs, addColGeneratedLines(cols))
+	allErrs = append(allErrs, addColExcludedCount(cols))
+	allErrs = append(allErrs, addColExcludedFiles(cols))
+	allErrs = append(allErrs, addColGoVersion(cols))
+	allErrs = append(allErrs, addColToolchain(cols))
+	allErrs = append(allErrs, addColGoVerConflicts(p, cols))
+	allErrs = append(allErrs, addColsDirectives(p, cols))
+	allErrs = append(allErrs, addColDeprecated(cols))
+	allErrs = append(allErrs, addColMissingSums(p, cols))
+	allErrs = append(allErrs, addColUnneededSums(p, cols))
+	allErrs = append(allErrs, addColCacheVersion(cols))
+	allErrs = append(allErrs, addColLicenceFile(cols))
+	allErrs = append(allErrs, addColModSize(cols))
 
 	allErrs = append(allErrs, cols.AddAlias(AliasLines, ColPkgLines))
 	allErrs = append(allErrs, cols.AddAlias(AliasLoC, ColPkgLines))
+	allErrs = append(allErrs, cols.AddAlias(AliasBlast, ColBlastLoC))
+	allErrs = append(allErrs, cols.AddAlias(AliasCa, ColAfferent))
+	allErrs = append(allErrs, cols.AddAlias(AliasCe, ColEfferent))
+	allErrs = append(allErrs, cols.AddAlias(AliasTestLoC, ColTestLines))
+	allErrs = append(allErrs, cols.AddAlias(AliasGenLoC, ColGeneratedLines))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(AliasStab,
+		ColLevel,
+		ColName,
+		ColAfferent,
+		ColEfferent,
+		ColInstability,
+		ColAbstractness,
+		ColMainSeqDist,
+	))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(AliasSize,
+		ColLevel,
+		ColName,
+		ColPackages,
+		ColPkgLines,
+		ColCodeLines,
+		ColCommentLines,
+		ColBlankLines,
+		ColGeneratedLines,
+	))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(AliasAPI,
+		ColLevel,
+		ColName,
+		ColAPITypes,
+		ColAPIFuncs,
+		ColAPIMethods,
+		ColAPIConsts,
+		ColAPIVars,
+		ColAPITotal,
+	))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(AliasTests,
+		ColLevel,
+		ColName,
+		ColPackages,
+		ColUntestedPkgs,
+		ColPkgLines,
+		ColTestLines,
+		ColTestRatio,
+	))
 
 	allErrs = append(allErrs, cols.AddReportableAlias(AliasFull,
 		ColLevel,
@@ -431,7 +1406,14 @@ func (p *prog) populateCols() *rptmaker.Cols[*prog, *modInfo] {
 		ColUseCountDirect,
 		ColUsedBy,
 		ColPackages,
+		ColCommands,
+		ColLibraries,
 		ColPkgLines,
+		ColTestLines,
+		ColGeneratedLines,
+		ColAPITotal,
+		ColBlastLoC,
+		ColScanErrors,
 	))
 
 	allErrs = append(allErrs, cols.AddReportableAlias(AliasDirect,
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// deprecatedUse records a requirement of a deprecated module
+type deprecatedUse struct {
+	user *modInfo
+	req  *ReqInfo
+}
+
+// deprecatedUses returns every requirement of the deprecated module by
+// those modules which are being shown. The uses are returned sorted by
+// the name of the requiring module.
+func (prog *prog) deprecatedUses(mi *modInfo) []deprecatedUse {
+	uses := []deprecatedUse{}
+
+	for _, user := range slices.SortedFunc(slices.Values(prog.mInfo),
+		cmpModNames) {
+		if ri, ok := user.Reqs[mi.Name]; ok {
+			uses = append(uses, deprecatedUse{user: user, req: ri})
+		}
+	}
+
+	return uses
+}
+
+// reportDeprecated prints every deprecated module in the collection along
+// with the deprecation message and the modules which still require it.
+func (prog *prog) reportDeprecated(w io.Writer) {
+	const useIndent = "    "
+
+	found := false
+
+	for _, mi := range slices.SortedFunc(maps.Values(prog.mm), cmpModNames) {
+		if mi.Deprecated == "" {
+			continue
+		}
+
+		found = true
+
+		fmt.Fprintf(w, "%s is deprecated: %s\n",
+			prog.displayName(mi), mi.Deprecated)
+
+		uses := prog.deprecatedUses(mi)
+		if len(uses) == 0 {
+			fmt.Fprintln(w, useIndent+"it is not required by any module")
+			continue
+		}
+
+		for _, u := range uses {
+			fmt.Fprintf(w, "%srequired by %s at %s (%s %s)\n",
+				useIndent,
+				prog.displayName(u.user), u.user.reqLocation(u.req),
+				u.req.kind(), u.req.Version)
+		}
+	}
+
+	if !found {
+		fmt.Fprintln(w, "no module is deprecated")
+	}
+}
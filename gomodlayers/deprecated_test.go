@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReportDeprecated(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		goModFiles []string
+		expOut     string
+	}{
+		{
+			ID: testhelper.MkID("none deprecated"),
+			goModFiles: []string{
+				"module example.com/A\n",
+				"module example.com/B\n" +
+					"require example.com/A v1.0.0\n",
+			},
+			expOut: "no module is deprecated\n",
+		},
+		{
+			ID: testhelper.MkID("deprecated and used"),
+			goModFiles: []string{
+				"// Deprecated: use example.com/A2 instead.\n" +
+					"module example.com/A\n",
+				"module example.com/B\n" +
+					"require example.com/A v1.0.0\n",
+				"module example.com/C\n" +
+					"require (\n" +
+					"\texample.com/B v1.0.0\n" +
+					"\texample.com/A v1.0.0 // indirect\n" +
+					")\n",
+				"// Deprecated: no longer maintained.\n" +
+					"module example.com/D\n",
+			},
+			expOut: "A is deprecated: use example.com/A2 instead.\n" +
+				"    required by B at test1/go.mod:2 (direct v1.0.0)\n" +
+				"    required by C at test2/go.mod:4 (indirect v1.0.0)\n" +
+				"D is deprecated: no longer maintained.\n" +
+				"    it is not required by any module\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = mkTestModMap(t, tc.goModFiles...)
+		prog.stripPrefix = "example.com/"
+		prog.populateModInfo()
+
+		var buf bytes.Buffer
+
+		prog.reportDeprecated(&buf)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
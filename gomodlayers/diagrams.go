@@ -0,0 +1,124 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// modEdge records that one module directly requires another
+type modEdge struct {
+	from *modInfo
+	to   *modInfo
+}
+
+// graphNodes returns the modules to be shown in a diagram of the
+// collection, sorted by level and then by name
+func (prog *prog) graphNodes() []*modInfo {
+	nodes := slices.Clone(prog.mInfo)
+	slices.SortFunc(nodes, func(a, b *modInfo) int {
+		return cmp.Or(cmp.Compare(a.Level, b.Level),
+			strings.Compare(a.Name, b.Name))
+	})
+
+	return nodes
+}
+
+// graphEdges returns the direct requirements between the modules being
+// shown. Requirements of modules which are not being shown are omitted.
+// The edges are sorted by the name of the required module and then by the
+// name of the requiring module.
+func (prog *prog) graphEdges() []modEdge {
+	edges := []modEdge{}
+
+	for _, mi := range slices.SortedFunc(slices.Values(prog.mInfo),
+		cmpModNames) {
+		for _, rbMi := range mi.ReqdByDirectly {
+			if prog.skipModInfo(rbMi) {
+				continue
+			}
+
+			edges = append(edges, modEdge{from: rbMi, to: mi})
+		}
+	}
+
+	return edges
+}
+
+// graphNodeIDs returns a map of module names to identifiers suitable for
+// use in the diagram languages which cannot use the module name directly
+func graphNodeIDs(nodes []*modInfo) map[string]string {
+	ids := make(map[string]string, len(nodes))
+	for i, mi := range nodes {
+		ids[mi.Name] = fmt.Sprintf("m%d", i)
+	}
+
+	return ids
+}
+
+// levelGroups calls the groupFunc for each level with the modules at that
+// level, in level order
+func levelGroups(nodes []*modInfo, groupFunc func(int, []*modInfo)) {
+	for start := 0; start < len(nodes); {
+		end := start + 1
+		for end < len(nodes) && nodes[end].Level == nodes[start].Level {
+			end++
+		}
+
+		groupFunc(nodes[start].Level, nodes[start:end])
+		start = end
+	}
+}
+
+// writeMermaid writes a Mermaid graph of the modules being shown and the
+// relationships between them. The modules are grouped by level.
+func (prog *prog) writeMermaid(w io.Writer) {
+	nodes := prog.graphNodes()
+	ids := graphNodeIDs(nodes)
+
+	fmt.Fprintln(w, "graph TD")
+
+	levelGroups(nodes, func(level int, mods []*modInfo) {
+		fmt.Fprintf(w, "    subgraph level%d [\"Level %d\"]\n", level, level)
+
+		for _, mi := range mods {
+			fmt.Fprintf(w, "        %s[\"%s\"]\n",
+				ids[mi.Name], strings.TrimPrefix(mi.Name, prog.stripPrefix))
+		}
+
+		fmt.Fprintln(w, "    end")
+	})
+
+	for _, e := range prog.graphEdges() {
+		fmt.Fprintf(w, "    %s --> %s\n", ids[e.from.Name], ids[e.to.Name])
+	}
+}
+
+// writePlantUML writes a PlantUML component diagram of the modules being
+// shown and the relationships between them. The modules are grouped by
+// level.
+func (prog *prog) writePlantUML(w io.Writer) {
+	nodes := prog.graphNodes()
+	ids := graphNodeIDs(nodes)
+
+	fmt.Fprintln(w, "@startuml")
+
+	levelGroups(nodes, func(level int, mods []*modInfo) {
+		fmt.Fprintf(w, "package \"Level %d\" {\n", level)
+
+		for _, mi := range mods {
+			fmt.Fprintf(w, "    component [%s] as %s\n",
+				strings.TrimPrefix(mi.Name, prog.stripPrefix), ids[mi.Name])
+		}
+
+		fmt.Fprintln(w, "}")
+	})
+
+	for _, e := range prog.graphEdges() {
+		fmt.Fprintf(w, "%s --> %s\n", ids[e.from.Name], ids[e.to.Name])
+	}
+
+	fmt.Fprintln(w, "@enduml")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDiagrams(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		hide     []string
+		write    func(*prog, *bytes.Buffer)
+		expected string
+	}{
+		{
+			ID: testhelper.MkID("mermaid"),
+			write: func(prog *prog, buf *bytes.Buffer) {
+				prog.writeMermaid(buf)
+			},
+			expected: "graph TD\n" +
+				"    subgraph level0 [\"Level 0\"]\n" +
+				"        m0[\"A\"]\n" +
+				"    end\n" +
+				"    subgraph level1 [\"Level 1\"]\n" +
+				"        m1[\"B\"]\n" +
+				"    end\n" +
+				"    subgraph level2 [\"Level 2\"]\n" +
+				"        m2[\"C\"]\n" +
+				"    end\n" +
+				"    subgraph level3 [\"Level 3\"]\n" +
+				"        m3[\"D\"]\n" +
+				"    end\n" +
+				"    m1 --> m0\n" +
+				"    m2 --> m1\n" +
+				"    m3 --> m2\n",
+		},
+		{
+			ID:   testhelper.MkID("mermaid, hidden module"),
+			hide: []string{"example.com/B"},
+			write: func(prog *prog, buf *bytes.Buffer) {
+				prog.writeMermaid(buf)
+			},
+			expected: "graph TD\n" +
+				"    subgraph level0 [\"Level 0\"]\n" +
+				"        m0[\"A\"]\n" +
+				"    end\n" +
+				"    subgraph level2 [\"Level 2\"]\n" +
+				"        m1[\"C\"]\n" +
+				"    end\n" +
+				"    subgraph level3 [\"Level 3\"]\n" +
+				"        m2[\"D\"]\n" +
+				"    end\n" +
+				"    m2 --> m1\n",
+		},
+		{
+			ID:   testhelper.MkID("plantuml"),
+			hide: []string{"example.com/D"},
+			write: func(prog *prog, buf *bytes.Buffer) {
+				prog.writePlantUML(buf)
+			},
+			expected: "@startuml\n" +
+				"package \"Level 0\" {\n" +
+				"    component [A] as m0\n" +
+				"}\n" +
+				"package \"Level 1\" {\n" +
+				"    component [B] as m1\n" +
+				"}\n" +
+				"package \"Level 2\" {\n" +
+				"    component [C] as m2\n" +
+				"}\n" +
+				"m1 --> m0\n" +
+				"m2 --> m1\n" +
+				"@enduml\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.stripPrefix = "example.com/"
+
+		for _, h := range tc.hide {
+			prog.hideModules[h] = true
+		}
+
+		prog.populateModInfo()
+
+		var buf bytes.Buffer
+
+		tc.write(prog, &buf)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(),
+			tc.expected)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// syntaxLine returns the line number of the go.mod file entry or zero if
+// the line is not known
+func syntaxLine(l *modfile.Line) int {
+	if l == nil {
+		return 0
+	}
+
+	return l.Start.Line
+}
+
+// retractDesc returns a description of the retracted versions
+func retractDesc(r *modfile.Retract) string {
+	desc := r.Low
+	if r.Low != r.High {
+		desc = "[" + r.Low + ", " + r.High + "]"
+	}
+
+	if r.Rationale != "" {
+		desc += " (" + r.Rationale + ")"
+	}
+
+	return desc
+}
+
+// retraction returns the retract directive covering the given version of
+// the module or nil if the version has not been retracted.
+func (mi *modInfo) retraction(version string) *modfile.Retract {
+	for _, r := range mi.Retracts {
+		if semver.Compare(r.Low, version) <= 0 &&
+			semver.Compare(version, r.High) <= 0 {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// retractDescs returns descriptions of the versions the module has
+// retracted
+func (mi *modInfo) retractDescs() []string {
+	descs := []string{}
+	for _, r := range mi.Retracts {
+		descs = append(descs, retractDesc(r))
+	}
+
+	return descs
+}
+
+// excludeDescs returns descriptions of the module versions excluded by the
+// module
+func (mi *modInfo) excludeDescs(stripPrefix string) []string {
+	descs := []string{}
+	for _, e := range mi.Excludes {
+		descs = append(descs,
+			strings.TrimPrefix(e.Mod.Path, stripPrefix)+" "+e.Mod.Version)
+	}
+
+	return descs
+}
+
+// toolNames returns the package paths of the tools given by the module
+func (mi *modInfo) toolNames() []string {
+	names := []string{}
+	for _, t := range mi.Tools {
+		names = append(names, t.Path)
+	}
+
+	return names
+}
+
+// godebugSettings returns the godebug settings given by the module in the
+// form key=value
+func (mi *modInfo) godebugSettings() []string {
+	settings := []string{}
+	for _, g := range mi.Godebugs {
+		settings = append(settings, g.Key+"="+g.Value)
+	}
+
+	return settings
+}
+
+// directiveProblems returns a description of each problem found with the
+// retract and exclude directives. A problem is reported if a module
+// requires a version of another module in the collection which that module
+// has retracted or if a module excludes a version of a module which
+// another module in the collection that it uses requires. The exclude
+// directives of a module only apply when it is the main module so the
+// modules which it does not use are not checked.
+func (prog *prog) directiveProblems() []string {
+	problems := []string{}
+	name := func(mi *modInfo) string {
+		return strings.TrimPrefix(mi.Name, prog.stripPrefix)
+	}
+
+	for _, mi := range slices.SortedFunc(slices.Values(prog.mInfo),
+		cmpModNames) {
+		for _, reqName := range slices.Sorted(maps.Keys(mi.Reqs)) {
+			ri := mi.Reqs[reqName]
+
+			r := ri.Mod.retraction(ri.Version)
+			if r == nil {
+				continue
+			}
+
+			problems = append(problems,
+				fmt.Sprintf("%s requires %s %s at %s"+
+					" but it is retracted at %s",
+					name(mi), name(ri.Mod), ri.Version, mi.reqLocation(ri),
+					ri.Mod.lineLocation(syntaxLine(r.Syntax))))
+		}
+
+		if len(mi.Excludes) == 0 {
+			continue
+		}
+
+		used := reachable([]*modInfo{mi}, usesDirectly, noDepthLimit)
+
+		for _, e := range mi.Excludes {
+			for _, other := range slices.SortedFunc(maps.Values(prog.mm),
+				cmpModNames) {
+				if _, ok := used[other.Name]; !ok || other == mi {
+					continue
+				}
+
+				ri, ok := other.Reqs[e.Mod.Path]
+				if !ok || ri.Version != e.Mod.Version {
+					continue
+				}
+
+				problems = append(problems,
+					fmt.Sprintf("%s excludes %s %s at %s"+
+						" but %s requires it at %s",
+						name(mi), name(ri.Mod), e.Mod.Version,
+						mi.lineLocation(syntaxLine(e.Syntax)),
+						name(other), other.reqLocation(ri)))
+			}
+		}
+	}
+
+	return problems
+}
+
+// reportDirectiveProblems reports any problems found with the retract and
+// exclude directives. It returns true if any problems are found.
+func (prog *prog) reportDirectiveProblems(w io.Writer) bool {
+	problems := prog.directiveProblems()
+	for _, p := range problems {
+		fmt.Fprintln(w, p)
+	}
+
+	return len(problems) > 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDirectiveProblems(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		goModFiles  []string
+		expProblems []string
+	}{
+		{
+			ID: testhelper.MkID("no problems"),
+			goModFiles: []string{
+				"module example.com/A\n" +
+					"retract v1.0.0\n",
+				"module example.com/B\n" +
+					"require example.com/A v1.1.0\n" +
+					"exclude example.com/A v1.0.0\n",
+			},
+			expProblems: []string{},
+		},
+		{
+			ID: testhelper.MkID("retracted and excluded"),
+			goModFiles: []string{
+				"module example.com/A\n" +
+					"retract [v1.0.0, v1.2.0] // broken\n",
+				"module example.com/B\n" +
+					"require example.com/A v1.1.0\n",
+				"module example.com/C\n" +
+					"require example.com/A v1.3.0\n" +
+					"require example.com/B v1.0.0\n" +
+					"exclude example.com/A v1.1.0\n",
+			},
+			expProblems: []string{
+				"B requires A v1.1.0 at test1/go.mod:2" +
+					" but it is retracted at test0/go.mod:2",
+				"C excludes A v1.1.0 at test2/go.mod:4" +
+					" but B requires it at test1/go.mod:2",
+			},
+		},
+		{
+			ID: testhelper.MkID("excluded by an unrelated module"),
+			goModFiles: []string{
+				"module example.com/A\n",
+				"module example.com/B\n" +
+					"require example.com/A v1.1.0\n",
+				"module example.com/C\n" +
+					"require example.com/A v1.3.0\n" +
+					"exclude example.com/A v1.1.0\n",
+			},
+			expProblems: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = mkTestModMap(t, tc.goModFiles...)
+		prog.stripPrefix = "example.com/"
+		prog.populateModInfo()
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "problems",
+			prog.directiveProblems(), tc.expProblems)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/version"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/errutil.mod/errutil"
+)
+
+const (
+	goSum           = "go.sum"
+	goSumFields     = 3
+	goModSumSuffix  = "/go.mod"
+	prunedGoVersion = "go1.17"
+)
+
+// goSumEntry records a line from a go.sum file
+type goSumEntry struct {
+	Path    string
+	Version string
+	Hash    string
+	Line    int
+}
+
+// isGoModHash returns true if the entry gives the hash of the go.mod file
+// of the module rather than the hash of the module's contents
+func (e goSumEntry) isGoModHash() bool {
+	return strings.HasSuffix(e.Version, goModSumSuffix)
+}
+
+// modVersion returns the version of the module that the entry is for
+func (e goSumEntry) modVersion() string {
+	return strings.TrimSuffix(e.Version, goModSumSuffix)
+}
+
+// parseGoSum parses the contents of a go.sum file, returning the entries
+// found. Blank lines are ignored. It returns an error if any line does not
+// have exactly three fields.
+func parseGoSum(fname string, contents []byte) ([]goSumEntry, error) {
+	entries := []goSumEntry{}
+
+	for i, line := range strings.Split(string(contents), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != goSumFields {
+			return nil, fmt.Errorf("%s:%d: malformed go.sum line: %q",
+				fname, i+1, line)
+		}
+
+		entries = append(entries, goSumEntry{
+			Path:    fields[0],
+			Version: fields[1],
+			Hash:    fields[2],
+			Line:    i + 1,
+		})
+	}
+
+	return entries, nil
+}
+
+// goSumName returns the name of the go.sum file alongside the module's
+// go.mod file
+func (mi *modInfo) goSumName() string {
+	return filepath.Join(filepath.Dir(mi.Loc.Source()), goSum)
+}
+
+// sumTarget returns the module path and version whose checksums are needed
+// for the requirement, taking account of any replace directives. It
+// returns false if the requirement is replaced by a local directory in
+// which case no checksums are needed.
+func (mi *modInfo) sumTarget(ri *ReqInfo) (string, string, bool) {
+	for _, r := range mi.Replaces {
+		if r.Old.Path != ri.Mod.Name ||
+			(r.Old.Version != "" && r.Old.Version != ri.Version) {
+			continue
+		}
+
+		if r.New.Version == "" {
+			return "", "", false
+		}
+
+		return r.New.Path, r.New.Version, true
+	}
+
+	return ri.Mod.Name, ri.Version, true
+}
+
+// listsAllProviders returns true if the module's go version is high enough
+// for its go.mod file to list every module that provides a package to its
+// build.
+func (mi *modInfo) listsAllProviders() bool {
+	return mi.GoVersion != "" &&
+		version.Compare(mi.goVersionOf(), prunedGoVersion) >= 0
+}
+
+// isReqdPathOrPrefix returns true if the path is that of a module in the
+// set of required paths or is a prefix of one of them. The go command keeps
+// the hashes of such prefix modules in the go.sum file so that it can check
+// that an import path is not ambiguous.
+func isReqdPathOrPrefix(path string, reqdPaths map[string]bool) bool {
+	for rp := range reqdPaths {
+		if rp == path || strings.HasPrefix(rp, path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setGoSums records the go.sum entries against the module. The hashes are
+// recorded against the requirements they are for. The entries giving the
+// hash of the contents of a module are recorded as unneeded if neither the
+// module nor any module within it is required. This is only done if the
+// module lists every module that provides a package; for older modules
+// the go.sum file may legitimately hold such entries.
+func (mi *modInfo) setGoSums(entries []goSumEntry) {
+	mi.GoSumRead = true
+	mi.UnneededSums = nil
+
+	needed := map[string]*ReqInfo{}
+	reqdPaths := map[string]bool{}
+
+	for _, ri := range mi.Reqs {
+		reqdPaths[ri.Mod.Name] = true
+
+		if path, ver, ok := mi.sumTarget(ri); ok {
+			needed[path+" "+ver] = ri
+			reqdPaths[path] = true
+		}
+	}
+
+	checkUnneeded := mi.listsAllProviders()
+
+	for _, e := range entries {
+		ri, ok := needed[e.Path+" "+e.modVersion()]
+
+		switch {
+		case ok && e.isGoModHash():
+			ri.GoModSumHash = e.Hash
+		case ok:
+			ri.GoSumHash = e.Hash
+		case checkUnneeded && !e.isGoModHash() &&
+			!isReqdPathOrPrefix(e.Path, reqdPaths):
+			mi.UnneededSums = append(mi.UnneededSums, e)
+		}
+	}
+}
+
+// missingSums returns the requirements of the module for which the go.sum
+// file is missing an entry. The go command needs the hash of the go.mod
+// file of every required module in order to load the module graph. If the
+// module lists every module that provides a package then every required
+// module provides a package and the hash of its contents is also needed.
+// The requirements are returned sorted by the name of the required module.
+// Nothing is returned if the go.sum file has not been read.
+func (mi *modInfo) missingSums() []*ReqInfo {
+	if !mi.GoSumRead {
+		return nil
+	}
+
+	missing := []*ReqInfo{}
+	needContentHash := mi.listsAllProviders()
+
+	for _, name := range slices.Sorted(maps.Keys(mi.Reqs)) {
+		ri := mi.Reqs[name]
+		if _, _, ok := mi.sumTarget(ri); !ok {
+			continue
+		}
+
+		if ri.GoModSumHash == "" || (needContentHash && ri.GoSumHash == "") {
+			missing = append(missing, ri)
+		}
+	}
+
+	return missing
+}
+
+// missingSumNames returns the names and versions of the requirements with
+// missing go.sum entries
+func (mi *modInfo) missingSumNames(stripPrefix string) []string {
+	names := []string{}
+	for _, ri := range mi.missingSums() {
+		names = append(names,
+			strings.TrimPrefix(ri.Mod.Name, stripPrefix)+" "+ri.Version)
+	}
+
+	return names
+}
+
+// unneededSumNames returns the names and versions of the modules with
+// unneeded go.sum entries
+func (mi *modInfo) unneededSumNames(stripPrefix string) []string {
+	names := []string{}
+	for _, e := range mi.UnneededSums {
+		names = append(names,
+			strings.TrimPrefix(e.Path, stripPrefix)+" "+e.Version)
+	}
+
+	return names
+}
+
+// readGoSums reads the go.sum file for each module in the collection and
+// records the entries against the module. A module with no go.sum file is
+// treated as if the file were empty. Any errors are returned in the error
+// map.
+func (mm modMap) readGoSums() *errutil.ErrMap {
+	errMap := errutil.NewErrMap()
+
+	for _, mi := range mm {
+		if mi.Loc == nil {
+			continue
+		}
+
+		fname := mi.goSumName()
+
+		contents, err := os.ReadFile(fname) //nolint:gosec
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errMap.AddError(fname, err)
+
+			continue
+		}
+
+		entries, err := parseGoSum(fname, contents)
+		if err != nil {
+			errMap.AddError(fname, err)
+
+			continue
+		}
+
+		mi.setGoSums(entries)
+	}
+
+	return errMap
+}
+
+// goSumProblems returns a description of each problem found with the go.sum
+// files. A problem is reported if a module requires a module for which its
+// go.sum file has no entry or if the go.sum file has an entry for a module
+// which is no longer required.
+func (prog *prog) goSumProblems() []string {
+	problems := []string{}
+	name := func(n string) string {
+		return strings.TrimPrefix(n, prog.stripPrefix)
+	}
+
+	for _, mi := range slices.SortedFunc(slices.Values(prog.mInfo),
+		cmpModNames) {
+		for _, ri := range mi.missingSums() {
+			problems = append(problems,
+				fmt.Sprintf("%s requires %s %s at %s"+
+					" but it is missing from %s",
+					name(mi.Name), name(ri.Mod.Name), ri.Version,
+					mi.reqLocation(ri), mi.goSumName()))
+		}
+
+		for _, e := range mi.UnneededSums {
+			problems = append(problems,
+				fmt.Sprintf("%s does not require %s %s"+
+					" but it is listed at %s:%d",
+					name(mi.Name), name(e.Path), e.Version,
+					mi.goSumName(), e.Line))
+		}
+	}
+
+	return problems
+}
+
+// reportGoSumProblems reports any problems found with the go.sum files. It
+// returns true if any problems are found.
+func (prog *prog) reportGoSumProblems(w io.Writer) bool {
+	problems := prog.goSumProblems()
+	for _, p := range problems {
+		fmt.Fprintln(w, p)
+	}
+
+	return len(problems) > 0
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParseGoSum(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		contents   string
+		expEntries []goSumEntry
+	}{
+		{
+			ID:         testhelper.MkID("empty"),
+			expEntries: []goSumEntry{},
+		},
+		{
+			ID: testhelper.MkID("good"),
+			contents: "example.com/A v1.0.0 h1:abc=\n" +
+				"\n" +
+				"example.com/A v1.0.0/go.mod h1:def=\n",
+			expEntries: []goSumEntry{
+				{Path: "example.com/A", Version: "v1.0.0", Hash: "h1:abc=", Line: 1},
+				{
+					Path:    "example.com/A",
+					Version: "v1.0.0/go.mod",
+					Hash:    "h1:def=",
+					Line:    3,
+				},
+			},
+		},
+		{
+			ID:       testhelper.MkID("bad"),
+			ExpErr:   testhelper.MkExpErr("go.sum:2: malformed go.sum line"),
+			contents: "example.com/A v1.0.0 h1:abc=\nexample.com/B v1.0.0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		entries, err := parseGoSum("go.sum", []byte(tc.contents))
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "entry count",
+				len(entries), len(tc.expEntries))
+
+			for i, e := range entries {
+				if i >= len(tc.expEntries) {
+					break
+				}
+
+				if e != tc.expEntries[i] {
+					t.Log(tc.IDStr())
+					t.Logf("\t: expected: %v", tc.expEntries[i])
+					t.Logf("\t:      got: %v", e)
+					t.Error("\t: unexpected go.sum entry")
+				}
+			}
+		}
+	}
+}
+
+func TestGoSumProblems(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		goMod       string
+		goSum       string
+		expProblems []string
+	}{
+		{
+			ID: testhelper.MkID("all present"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require example.com/X v1.0.0\n",
+			goSum: "example.com/X v1.0.0 h1:x=\n" +
+				"example.com/X v1.0.0/go.mod h1:xm=\n" +
+				"example.com/Y v0.9.0/go.mod h1:ym=\n",
+			expProblems: []string{},
+		},
+		{
+			ID: testhelper.MkID("missing and unneeded"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require (\n" +
+				"\texample.com/X v1.0.0\n" +
+				"\texample.com/Y v1.0.0\n" +
+				")\n",
+			goSum: "example.com/X v1.0.0 h1:x=\n" +
+				"example.com/X v1.0.0/go.mod h1:xm=\n" +
+				"example.com/V v0.9.0 h1:v=\n" +
+				"example.com/V v0.9.0/go.mod h1:vm=\n",
+			expProblems: []string{
+				"A requires Y v1.0.0 at test0/go.mod:5" +
+					" but it is missing from test0/go.sum",
+				"A does not require V v0.9.0" +
+					" but it is listed at test0/go.sum:3",
+			},
+		},
+		{
+			ID: testhelper.MkID("content hash missing"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require example.com/X v1.0.0\n",
+			goSum: "example.com/X v1.0.0/go.mod h1:xm=\n",
+			expProblems: []string{
+				"A requires X v1.0.0 at test0/go.mod:3" +
+					" but it is missing from test0/go.sum",
+			},
+		},
+		{
+			ID: testhelper.MkID("other version of a required module"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require example.com/X v1.0.0\n",
+			goSum: "example.com/X v0.9.0 h1:x9=\n" +
+				"example.com/X v1.0.0 h1:x=\n" +
+				"example.com/X v1.0.0/go.mod h1:xm=\n",
+			expProblems: []string{},
+		},
+		{
+			ID: testhelper.MkID("ambiguous import check"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require example.com/P/storage v1.0.0\n",
+			goSum: "example.com/P v0.100.0 h1:p=\n" +
+				"example.com/P v0.100.0/go.mod h1:pm=\n" +
+				"example.com/P/storage v1.0.0 h1:s=\n" +
+				"example.com/P/storage v1.0.0/go.mod h1:sm=\n" +
+				"example.com/Pstorage v1.0.0 h1:ps=\n",
+			expProblems: []string{
+				"A does not require Pstorage v1.0.0" +
+					" but it is listed at test0/go.sum:5",
+			},
+		},
+		{
+			ID: testhelper.MkID("old go version"),
+			goMod: "module example.com/A\n" +
+				"go 1.16\n" +
+				"require example.com/X v1.0.0\n",
+			goSum: "example.com/X v1.0.0/go.mod h1:xm=\n" +
+				"example.com/Y v0.9.0 h1:y=\n",
+			expProblems: []string{},
+		},
+		{
+			ID: testhelper.MkID("replaced"),
+			goMod: "module example.com/A\n" +
+				"go 1.21\n" +
+				"require (\n" +
+				"\texample.com/X v1.0.0\n" +
+				"\texample.com/Y v1.0.0\n" +
+				")\n" +
+				"replace example.com/X => example.com/Z v1.1.0\n" +
+				"replace example.com/Y => ../Y\n",
+			goSum: "example.com/Z v1.1.0 h1:z=\n" +
+				"example.com/Z v1.1.0/go.mod h1:zm=\n",
+			expProblems: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = mkTestModMap(t, tc.goMod)
+		prog.stripPrefix = "example.com/"
+		prog.populateModInfo()
+
+		entries, err := parseGoSum("test0/go.sum", []byte(tc.goSum))
+		if err != nil {
+			t.Fatalf("%s: cannot parse the go.sum: %s", tc.IDStr(), err)
+		}
+
+		prog.mm["example.com/A"].setGoSums(entries)
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "problems",
+			prog.goSumProblems(), tc.expProblems)
+	}
+}
+
+func TestSetGoSumsHashes(t *testing.T) {
+	mm := mkTestModMap(t,
+		"module example.com/A\n"+
+			"require example.com/X v1.0.0\n")
+	mi := mm["example.com/A"]
+
+	testhelper.DiffInt(t, "before reading", "missing count",
+		len(mi.missingSums()), 0)
+
+	mi.setGoSums([]goSumEntry{
+		{Path: "example.com/X", Version: "v1.0.0", Hash: "h1:x="},
+		{Path: "example.com/X", Version: "v1.0.0/go.mod", Hash: "h1:xm="},
+	})
+
+	ri := mi.Reqs["example.com/X"]
+	testhelper.DiffString(t, "after reading", "hash", ri.GoSumHash, "h1:x=")
+	testhelper.DiffString(t, "after reading", "go.mod hash",
+		ri.GoModSumHash, "h1:xm=")
+	testhelper.DiffInt(t, "after reading", "missing count",
+		len(mi.missingSums()), 0)
+}
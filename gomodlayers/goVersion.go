@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"go/version"
+	"io"
+	"slices"
+	"strings"
+)
+
+// goVersionOf returns the go version of the module in the form used by the
+// go/version package. It returns the empty string if the module has no go
+// directive.
+func (mi *modInfo) goVersionOf() string {
+	if mi.GoVersion == "" {
+		return ""
+	}
+
+	return "go" + mi.GoVersion
+}
+
+// cmpModNames compares the modules by name
+func cmpModNames(a, b *modInfo) int {
+	return strings.Compare(a.Name, b.Name)
+}
+
+// goVersionConflicts returns those modules which use this module, directly
+// or indirectly, but which declare a lower go version than this module
+// does. Such modules may fail to build once they are upgraded to use the
+// version of this module which needs the higher go version. Modules which
+// have no go directive (including external modules) are not checked. The
+// modules are returned sorted by name.
+func (mi *modInfo) goVersionConflicts() []*modInfo {
+	goVer := mi.goVersionOf()
+	if goVer == "" {
+		return nil
+	}
+
+	conflicts := slices.DeleteFunc(usedBy(mi), func(ub *modInfo) bool {
+		ubVer := ub.goVersionOf()
+		return ubVer == "" || version.Compare(ubVer, goVer) >= 0
+	})
+
+	slices.SortFunc(conflicts, cmpModNames)
+
+	return conflicts
+}
+
+// goVersionConflictNames returns the names of the modules with conflicting
+// go versions, each followed by the go version it declares
+func (mi *modInfo) goVersionConflictNames(stripPrefix string) []string {
+	names := []string{}
+	for _, c := range mi.goVersionConflicts() {
+		names = append(names,
+			strings.TrimPrefix(c.Name, stripPrefix)+" (go "+c.GoVersion+")")
+	}
+
+	return names
+}
+
+// reportGoVersionConflicts reports every module which declares a higher go
+// version than a module which uses it. It returns true if any such module is
+// found.
+func (prog *prog) reportGoVersionConflicts(w io.Writer) bool {
+	found := false
+
+	for _, mi := range slices.SortedFunc(slices.Values(prog.mInfo),
+		cmpModNames) {
+		conflicts := mi.goVersionConflictNames(prog.stripPrefix)
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		found = true
+
+		fmt.Fprintf(w, "%s requires go %s but is used by:\n",
+			strings.TrimPrefix(mi.Name, prog.stripPrefix), mi.GoVersion)
+
+		for _, c := range conflicts {
+			fmt.Fprintln(w, "    "+c)
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReportGoVersionConflicts(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		goModFiles []string
+		expFound   bool
+		expOut     string
+	}{
+		{
+			ID: testhelper.MkID("no conflicts"),
+			goModFiles: []string{
+				"module example.com/A\ngo 1.21\n",
+				"module example.com/B\ngo 1.22\n" +
+					"require example.com/A v1.0.0\n",
+			},
+		},
+		{
+			ID: testhelper.MkID("direct and indirect conflicts"),
+			goModFiles: []string{
+				"module example.com/A\ngo 1.22.1\ntoolchain go1.23.0\n",
+				"module example.com/B\ngo 1.22\n" +
+					"require example.com/A v1.0.0\n",
+				"module example.com/C\ngo 1.21\n" +
+					"require (\n" +
+					"\texample.com/B v1.0.0\n" +
+					"\texample.com/A v1.0.0 // indirect\n" +
+					")\n",
+				"module example.com/D\n" +
+					"require example.com/A v1.0.0\n",
+			},
+			expFound: true,
+			expOut: "A requires go 1.22.1 but is used by:\n" +
+				"    B (go 1.22)\n" +
+				"    C (go 1.21)\n" +
+				"B requires go 1.22 but is used by:\n" +
+				"    C (go 1.21)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = mkTestModMap(t, tc.goModFiles...)
+		prog.stripPrefix = "example.com/"
+		prog.populateModInfo()
+
+		var buf bytes.Buffer
+
+		found := prog.reportGoVersionConflicts(&buf)
+
+		testhelper.DiffBool(t, tc.IDStr(), "found", found, tc.expFound)
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
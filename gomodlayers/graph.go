@@ -0,0 +1,66 @@
+package main
+
+import "slices"
+
+// noDepthLimit is the value of a maximum depth which indicates that there is
+// no limit on how far a search of the module graph should go
+const noDepthLimit = -1
+
+// modEdgeFunc is the type of a function which returns the modules at the
+// other end of the edges leaving the given module.
+type modEdgeFunc func(*modInfo) []*modInfo
+
+// usesDirectly returns the modules that the given module requires directly
+func usesDirectly(mi *modInfo) []*modInfo { return mi.DirectReqs }
+
+// usedByDirectly returns the modules that directly require the given module
+func usedByDirectly(mi *modInfo) []*modInfo { return mi.ReqdByDirectly }
+
+// usedBy returns the modules that require the given module either
+// directly or indirectly
+func usedBy(mi *modInfo) []*modInfo {
+	return slices.Concat(mi.ReqdByDirectly, mi.ReqdByIndirectly)
+}
+
+// reachable performs a breadth-first search of the module graph from the
+// start modules, following the edges given by the next function. It returns
+// a map of the names of every module reached to the number of edges on the
+// shortest path to it from any of the start modules. The start modules are
+// themselves at a distance of zero. The search will not follow paths longer
+// than maxDepth edges unless maxDepth is set to noDepthLimit.
+func reachable(
+	start []*modInfo, next modEdgeFunc, maxDepth int,
+) map[string]int {
+	dist := make(map[string]int, len(start))
+	queue := make([]*modInfo, 0, len(start))
+
+	for _, mi := range start {
+		if _, seen := dist[mi.Name]; seen {
+			continue
+		}
+
+		dist[mi.Name] = 0
+		queue = append(queue, mi)
+	}
+
+	for len(queue) > 0 {
+		mi := queue[0]
+		queue = queue[1:]
+
+		d := dist[mi.Name]
+		if maxDepth != noDepthLimit && d >= maxDepth {
+			continue
+		}
+
+		for _, nmi := range next(mi) {
+			if _, seen := dist[nmi.Name]; seen {
+				continue
+			}
+
+			dist[nmi.Name] = d + 1
+			queue = append(queue, nmi)
+		}
+	}
+
+	return dist
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// attrType gives the type of an exported attribute
+type attrType int
+
+const (
+	attrInt attrType = iota
+	attrBool
+	attrString
+)
+
+// graphMLName returns the name of the type as used in GraphML files
+func (at attrType) graphMLName() string {
+	switch at {
+	case attrInt:
+		return "int"
+	case attrBool:
+		return "boolean"
+	}
+
+	return "string"
+}
+
+// gexfName returns the name of the type as used in GEXF files
+func (at attrType) gexfName() string {
+	switch at {
+	case attrInt:
+		return "integer"
+	case attrBool:
+		return "boolean"
+	}
+
+	return "string"
+}
+
+// exportAttr describes an attribute of a node or an edge in the exported
+// graph
+type exportAttr[T any] struct {
+	id  string
+	typ attrType
+	val func(T) string
+}
+
+// exportEdge records a requirement of one module by another in the
+// exported graph
+type exportEdge struct {
+	from *modInfo
+	to   *modInfo
+	req  *ReqInfo
+}
+
+// nodeAttrs gives the attributes of the nodes in the exported graph
+var nodeAttrs = []exportAttr[*modInfo]{
+	{
+		id:  "level",
+		typ: attrInt,
+		val: func(mi *modInfo) string { return strconv.Itoa(mi.Level) },
+	},
+	{
+		id:  "lines-of-code",
+		typ: attrInt,
+		val: func(mi *modInfo) string { return strconv.Itoa(mi.LinesOfCode) },
+	},
+	{
+		id:  "packages",
+		typ: attrInt,
+		val: func(mi *modInfo) string { return strconv.Itoa(len(mi.Packages)) },
+	},
+	{
+		id:  "external",
+		typ: attrBool,
+		val: func(mi *modInfo) string { return strconv.FormatBool(mi.Loc == nil) },
+	},
+	{
+		id:  "use-count",
+		typ: attrInt,
+		val: func(mi *modInfo) string {
+			return strconv.Itoa(len(mi.ReqdByDirectly) + len(mi.ReqdByIndirectly))
+		},
+	},
+	{
+		id:  "direct-use-count",
+		typ: attrInt,
+		val: func(mi *modInfo) string {
+			return strconv.Itoa(len(mi.ReqdByDirectly))
+		},
+	},
+}
+
+// edgeAttrs gives the attributes of the edges in the exported graph
+var edgeAttrs = []exportAttr[exportEdge]{
+	{
+		id:  "kind",
+		typ: attrString,
+		val: func(e exportEdge) string { return e.req.kind() },
+	},
+	{
+		id:  "version",
+		typ: attrString,
+		val: func(e exportEdge) string { return e.req.Version },
+	},
+}
+
+// exportGraph returns the nodes and edges of the graph to be exported. The
+// nodes are the modules being shown together with any external modules
+// that they require, unless the external modules are hidden. The edges
+// are all the requirements, direct or indirect, between the nodes. Both
+// are returned in a consistent order.
+func (prog *prog) exportGraph() ([]*modInfo, []exportEdge) {
+	inGraph := map[string]*modInfo{}
+
+	for _, mi := range prog.mInfo {
+		inGraph[mi.Name] = mi
+
+		for _, ri := range mi.Reqs {
+			if ri.Mod.Loc == nil && !prog.hideModules[ri.Mod.Name] {
+				inGraph[ri.Mod.Name] = ri.Mod
+			}
+		}
+	}
+
+	nodes := slices.SortedFunc(maps.Values(inGraph), cmpModNames)
+	edges := []exportEdge{}
+
+	for _, mi := range nodes {
+		for _, name := range slices.Sorted(maps.Keys(mi.Reqs)) {
+			ri := mi.Reqs[name]
+			if _, ok := inGraph[name]; ok {
+				edges = append(edges, exportEdge{from: mi, to: ri.Mod, req: ri})
+			}
+		}
+	}
+
+	return nodes, edges
+}
+
+// graphMLKey is a GraphML attribute declaration
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// graphMLData is a GraphML attribute value
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode is a GraphML node
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge is a GraphML edge
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphML is the top-level element of a GraphML file
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// gexfAttribute is a GEXF attribute declaration
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+// gexfAttributes is a set of GEXF attribute declarations
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+// gexfAttValue is a GEXF attribute value
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfNode is a GEXF node
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+// gexfEdge is a GEXF edge
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+// gexf is the top-level element of a GEXF file
+type gexf struct {
+	XMLName xml.Name `xml:"gexf"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Graph   struct {
+		DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+		Attributes      []gexfAttributes `xml:"attributes"`
+		Nodes           []gexfNode       `xml:"nodes>node"`
+		Edges           []gexfEdge       `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+// mkGraphML returns the GraphML representation of the graph
+func (prog *prog) mkGraphML(nodes []*modInfo, edges []exportEdge) graphML {
+	g := graphML{XMLNS: "http://graphml.graphdrawing.org/xmlns"}
+	g.Graph.ID = "modules"
+	g.Graph.EdgeDefault = "directed"
+
+	g.Keys = append(g.Keys, graphMLKey{
+		ID: "name", For: "node", AttrName: "name", AttrType: "string",
+	})
+	for _, a := range nodeAttrs {
+		g.Keys = append(g.Keys, graphMLKey{
+			ID: a.id, For: "node", AttrName: a.id, AttrType: a.typ.graphMLName(),
+		})
+	}
+
+	for _, a := range edgeAttrs {
+		g.Keys = append(g.Keys, graphMLKey{
+			ID: a.id, For: "edge", AttrName: a.id, AttrType: a.typ.graphMLName(),
+		})
+	}
+
+	ids := graphNodeIDs(nodes)
+
+	for _, mi := range nodes {
+		n := graphMLNode{
+			ID: ids[mi.Name],
+			Data: []graphMLData{{
+				Key:   "name",
+				Value: prog.graphName(mi),
+			}},
+		}
+		for _, a := range nodeAttrs {
+			n.Data = append(n.Data, graphMLData{Key: a.id, Value: a.val(mi)})
+		}
+
+		g.Graph.Nodes = append(g.Graph.Nodes, n)
+	}
+
+	for i, e := range edges {
+		ge := graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: ids[e.from.Name],
+			Target: ids[e.to.Name],
+		}
+		for _, a := range edgeAttrs {
+			ge.Data = append(ge.Data, graphMLData{Key: a.id, Value: a.val(e)})
+		}
+
+		g.Graph.Edges = append(g.Graph.Edges, ge)
+	}
+
+	return g
+}
+
+// mkGEXF returns the GEXF representation of the graph
+func (prog *prog) mkGEXF(nodes []*modInfo, edges []exportEdge) gexf {
+	g := gexf{XMLNS: "http://gexf.net/1.3", Version: "1.3"}
+	g.Graph.DefaultEdgeType = "directed"
+
+	nodeDecls := gexfAttributes{Class: "node"}
+	for _, a := range nodeAttrs {
+		nodeDecls.Attributes = append(nodeDecls.Attributes,
+			gexfAttribute{ID: a.id, Title: a.id, Type: a.typ.gexfName()})
+	}
+
+	edgeDecls := gexfAttributes{Class: "edge"}
+	for _, a := range edgeAttrs {
+		edgeDecls.Attributes = append(edgeDecls.Attributes,
+			gexfAttribute{ID: a.id, Title: a.id, Type: a.typ.gexfName()})
+	}
+
+	g.Graph.Attributes = []gexfAttributes{nodeDecls, edgeDecls}
+
+	ids := graphNodeIDs(nodes)
+
+	for _, mi := range nodes {
+		n := gexfNode{
+			ID:    ids[mi.Name],
+			Label: prog.graphName(mi),
+		}
+		for _, a := range nodeAttrs {
+			n.AttValues = append(n.AttValues,
+				gexfAttValue{For: a.id, Value: a.val(mi)})
+		}
+
+		g.Graph.Nodes = append(g.Graph.Nodes, n)
+	}
+
+	for i, e := range edges {
+		ge := gexfEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: ids[e.from.Name],
+			Target: ids[e.to.Name],
+		}
+		for _, a := range edgeAttrs {
+			ge.AttValues = append(ge.AttValues,
+				gexfAttValue{For: a.id, Value: a.val(e)})
+		}
+
+		g.Graph.Edges = append(g.Graph.Edges, ge)
+	}
+
+	return g
+}
+
+// graphName returns the module name as shown in the exported graph
+func (prog *prog) graphName(mi *modInfo) string {
+	return strings.TrimPrefix(mi.Name, prog.stripPrefix)
+}
+
+// writeXML writes the value as an indented XML document
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// writeGraphML writes the module graph in the GraphML format
+func (prog *prog) writeGraphML(w io.Writer) {
+	nodes, edges := prog.exportGraph()
+
+	if err := writeXML(w, prog.mkGraphML(nodes, edges)); err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't write the GraphML file:", err)
+		prog.setExitStatus(1)
+	}
+}
+
+// writeGEXF writes the module graph in the GEXF format
+func (prog *prog) writeGEXF(w io.Writer) {
+	nodes, edges := prog.exportGraph()
+
+	if err := writeXML(w, prog.mkGEXF(nodes, edges)); err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't write the GEXF file:", err)
+		prog.setExitStatus(1)
+	}
+}
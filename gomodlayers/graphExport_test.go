@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestExportGraph(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		hide     []string
+		expNodes []string
+		expEdges []string
+	}{
+		{
+			ID:       testhelper.MkID("all modules"),
+			expNodes: []string{"A", "B", "C", "D", "X"},
+			expEdges: []string{
+				"B -> A v1.0.0 (direct)",
+				"C -> A v1.0.0 (indirect)",
+				"C -> B v1.0.0 (direct)",
+				"C -> X v1.0.0 (direct)",
+				"D -> A v1.0.0 (indirect)",
+				"D -> B v1.0.0 (indirect)",
+				"D -> C v1.0.0 (direct)",
+			},
+		},
+		{
+			ID:       testhelper.MkID("hidden modules"),
+			hide:     []string{"example.com/B", "example.com/X"},
+			expNodes: []string{"A", "C", "D"},
+			expEdges: []string{
+				"C -> A v1.0.0 (indirect)",
+				"D -> A v1.0.0 (indirect)",
+				"D -> C v1.0.0 (direct)",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.stripPrefix = "example.com/"
+
+		for _, h := range tc.hide {
+			prog.hideModules[h] = true
+		}
+
+		prog.populateModInfo()
+
+		nodes, edges := prog.exportGraph()
+
+		nodeNames := []string{}
+		for _, mi := range nodes {
+			nodeNames = append(nodeNames, prog.graphName(mi))
+		}
+
+		edgeDescs := []string{}
+		for _, e := range edges {
+			edgeDescs = append(edgeDescs,
+				prog.graphName(e.from)+" -> "+prog.graphName(e.to)+
+					" "+e.req.Version+" ("+e.req.kind()+")")
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "nodes",
+			nodeNames, tc.expNodes)
+		testhelper.DiffStringSlice(t, tc.IDStr(), "edges",
+			edgeDescs, tc.expEdges)
+	}
+}
+
+func TestWriteGraphFormats(t *testing.T) {
+	prog := newProg()
+	prog.mm = testModMapABCD(t)
+	prog.stripPrefix = "example.com/"
+	prog.populateModInfo()
+
+	var gmlBuf, gexfBuf bytes.Buffer
+
+	prog.writeGraphML(&gmlBuf)
+	prog.writeGEXF(&gexfBuf)
+
+	var gml graphML
+	if err := xml.Unmarshal(gmlBuf.Bytes(), &gml); err != nil {
+		t.Fatalf("cannot parse the GraphML output: %s", err)
+	}
+
+	testhelper.DiffInt(t, "GraphML", "nodes", len(gml.Graph.Nodes), 5)
+	testhelper.DiffInt(t, "GraphML", "edges", len(gml.Graph.Edges), 7)
+
+	var gx gexf
+	if err := xml.Unmarshal(gexfBuf.Bytes(), &gx); err != nil {
+		t.Fatalf("cannot parse the GEXF output: %s", err)
+	}
+
+	testhelper.DiffInt(t, "GEXF", "nodes", len(gx.Graph.Nodes), 5)
+	testhelper.DiffInt(t, "GEXF", "edges", len(gx.Graph.Edges), 7)
+	testhelper.DiffString(t, "GEXF", "last node label",
+		gx.Graph.Nodes[4].Label, "X")
+	testhelper.DiffString(t, "GEXF", "last node external",
+		gx.Graph.Nodes[4].AttValues[3].Value, "true")
+	testhelper.DiffInt(t, "exit status", "value", prog.exitStatus, 0)
+}
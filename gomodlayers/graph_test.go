@@ -0,0 +1,84 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReachable(t *testing.T) {
+	mm := testModMapABCD(t)
+
+	testCases := []struct {
+		testhelper.ID
+		start    []string
+		next     modEdgeFunc
+		maxDepth int
+		expDist  map[string]int
+	}{
+		{
+			ID:       testhelper.MkID("used by, no limit"),
+			start:    []string{"example.com/A"},
+			next:     usedByDirectly,
+			maxDepth: noDepthLimit,
+			expDist: map[string]int{
+				"example.com/A": 0,
+				"example.com/B": 1,
+				"example.com/C": 2,
+				"example.com/D": 3,
+			},
+		},
+		{
+			ID:       testhelper.MkID("used by, depth 1"),
+			start:    []string{"example.com/A"},
+			next:     usedByDirectly,
+			maxDepth: 1,
+			expDist: map[string]int{
+				"example.com/A": 0,
+				"example.com/B": 1,
+			},
+		},
+		{
+			ID:       testhelper.MkID("uses, depth 0"),
+			start:    []string{"example.com/D"},
+			next:     usesDirectly,
+			maxDepth: 0,
+			expDist: map[string]int{
+				"example.com/D": 0,
+			},
+		},
+		{
+			ID:       testhelper.MkID("uses, multiple starts"),
+			start:    []string{"example.com/D", "example.com/B"},
+			next:     usesDirectly,
+			maxDepth: noDepthLimit,
+			expDist: map[string]int{
+				"example.com/A": 1,
+				"example.com/B": 0,
+				"example.com/C": 1,
+				"example.com/D": 0,
+				"example.com/X": 2,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		start := []*modInfo{}
+		for _, name := range tc.start {
+			start = append(start, mm[name])
+		}
+
+		dist := reachable(start, tc.next, tc.maxDepth)
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "modules reached",
+			slices.Sorted(maps.Keys(dist)),
+			slices.Sorted(maps.Keys(tc.expDist)))
+
+		for name, expD := range tc.expDist {
+			testhelper.DiffInt(t, tc.IDStr(), "distance to "+name,
+				dist[name], expD)
+		}
+	}
+}
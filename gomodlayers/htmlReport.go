@@ -0,0 +1,468 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+)
+
+// htmlColDesc describes a column of one of the HTML tables
+type htmlColDesc struct {
+	ID      rptmaker.ColID
+	Heading string
+	Desc    string
+	Numeric bool
+}
+
+// htmlCell holds the contents of a cell of one of the HTML tables
+type htmlCell struct {
+	Lines   []string
+	SortKey string
+	Numeric bool
+	Link    string
+}
+
+// htmlLevel holds the table rows for the modules at a given level
+type htmlLevel struct {
+	Level int
+	Rows  [][]htmlCell
+}
+
+// htmlModLink gives the name of a module and the anchor of its details. The
+// anchor is empty if the module has no details in the report.
+type htmlModLink struct {
+	Name   string
+	Anchor string
+}
+
+// htmlModList is a titled list of modules
+type htmlModList struct {
+	Title string
+	Mods  []htmlModLink
+}
+
+// htmlModule holds the details of a module shown in the HTML report
+type htmlModule struct {
+	Name     string
+	Anchor   string
+	Level    int
+	Lists    []htmlModList
+	Packages [][]htmlCell
+}
+
+// htmlReport holds all the values needed to generate the HTML report
+type htmlReport struct {
+	Title    string
+	Intro    string
+	Cols     []htmlColDesc
+	Levels   []htmlLevel
+	PkgCols  []htmlColDesc
+	Modules  []htmlModule
+	ColCount int
+}
+
+// htmlTemplate is the template for the HTML report. The report is written
+// as a single file with all the styles and scripts it needs so that it can
+// be viewed without network access.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td {
+  border: 1px solid #ccc; padding: 0.2em 0.5em;
+  text-align: left; vertical-align: top;
+}
+th.sortable { background: #eee; cursor: pointer; }
+th.sortable::after { color: #888; content: " \2195"; }
+tr.level-heading th { background: #dde8f5; }
+td.num { text-align: right; }
+dt { font-family: monospace; font-weight: bold; }
+section.module { border-top: 1px solid #ccc; margin-top: 1em; }
+</style>
+<script>
+var moduleRows = null;
+var sortCol = -1;
+var sortAsc = true;
+
+function moduleTable() {
+  return document.querySelector("#modules tbody");
+}
+
+function saveRows() {
+  if (moduleRows === null) {
+    moduleRows = Array.from(moduleTable().rows);
+  }
+}
+
+function sortModules(col) {
+  saveRows();
+  if (sortCol === col) {
+    sortAsc = !sortAsc;
+  } else {
+    sortCol = col;
+    sortAsc = true;
+  }
+
+  var rows = moduleRows.filter(function (r) {
+    return r.classList.contains("module");
+  });
+  var numeric = document.querySelectorAll("#modules thead th")[col]
+    .dataset.type === "num";
+  rows.sort(function (a, b) {
+    var x = a.cells[col].dataset.sort;
+    var y = b.cells[col].dataset.sort;
+    // blank cells are always placed after the others
+    if (x === "" || y === "") {
+      return (x === "") - (y === "");
+    }
+    var c = numeric ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+    return sortAsc ? c : -c;
+  });
+
+  var body = moduleTable();
+  body.replaceChildren.apply(body, rows);
+}
+
+function groupByLevel() {
+  saveRows();
+  sortCol = -1;
+
+  var body = moduleTable();
+  body.replaceChildren.apply(body, moduleRows);
+}
+</script>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{- if .Intro}}
+<p>{{.Intro}}</p>
+<dl>
+{{- range .Cols}}
+<dt>{{.ID}}</dt>
+<dd>{{.Desc}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+<p>
+Click on a column heading to sort the modules by that column.
+<button type="button" onclick="groupByLevel()">Group by level</button>
+</p>
+<table id="modules">
+<thead>
+<tr>
+{{- range $i, $c := .Cols}}
+<th class="sortable" title="{{$c.ID}}"
+ data-type="{{if $c.Numeric}}num{{else}}str{{end}}"
+ onclick="sortModules({{$i}})">
+{{- $c.Heading}}</th>
+{{- end}}
+</tr>
+</thead>
+<tbody>
+{{- range .Levels}}
+<tr class="level-heading"><th colspan="{{$.ColCount}}">
+{{- "Level "}}{{.Level}}</th></tr>
+{{- range .Rows}}
+<tr class="module">
+{{- template "cells" .}}
+</tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+<h2>Module details</h2>
+{{- range .Modules}}
+<section class="module" id="{{.Anchor}}">
+<h3>{{.Name}}</h3>
+<p>Level {{.Level}}</p>
+{{- range .Lists}}
+{{- if .Mods}}
+<h4>{{.Title}}</h4>
+<ul>
+{{- range .Mods}}
+<li>
+{{- if .Anchor}}<a href="#{{.Anchor}}">{{.Name}}</a>
+{{- else}}{{.Name}} (external){{end -}}
+</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+{{- if .Packages}}
+<h4>Packages</h4>
+<table>
+<thead>
+<tr>
+{{- range $.PkgCols}}
+<th title="{{.ID}}">{{.Heading}}</th>
+{{- end}}
+</tr>
+</thead>
+<tbody>
+{{- range .Packages}}
+<tr>
+{{- template "cells" .}}
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+{{define "cells"}}
+{{- range .}}
+<td{{if .Numeric}} class="num"{{end}} data-sort="{{.SortKey}}">
+{{- if .Link}}<a href="#{{.Link}}">{{end}}
+{{- range $i, $l := .Lines}}{{if $i}}<br>{{end}}{{$l}}{{end}}
+{{- if .Link}}</a>{{end -}}
+</td>
+{{- end}}
+{{- end}}
+`))
+
+// htmlAnchor returns the anchor for the module's details in the HTML
+// report
+func htmlAnchor(mi *modInfo) string {
+	return "module-" + mi.Name
+}
+
+// mkHTMLCell returns the text formatted for use in an HTML table cell
+func mkHTMLCell(text string) htmlCell {
+	return htmlCell{
+		Lines:   strings.Split(text, "\n"),
+		SortKey: text,
+	}
+}
+
+// setNumericCols marks those columns, and the cells in them, where every
+// cell which is not blank holds a number. These columns are sorted by value
+// rather than as strings. A column with only blank cells is not numeric.
+func setNumericCols(cols []htmlColDesc, rows [][]htmlCell) {
+	for i := range cols {
+		numeric, blank := true, true
+
+		for _, row := range rows {
+			if row[i].SortKey == "" {
+				continue
+			}
+
+			blank = false
+
+			if _, err := strconv.ParseFloat(row[i].SortKey, 64); err != nil {
+				numeric = false
+				break
+			}
+		}
+
+		cols[i].Numeric = numeric && !blank
+
+		for _, row := range rows {
+			row[i].Numeric = cols[i].Numeric
+		}
+	}
+}
+
+// mkHTMLRow returns the cells of the table row for the value
+func mkHTMLRow[T any](cts []colTexter[T], v T) ([]htmlCell, error) {
+	row := make([]htmlCell, 0, len(cts))
+
+	for _, ct := range cts {
+		text, err := ct.text(v)
+		if err != nil {
+			return nil, err
+		}
+
+		row = append(row, mkHTMLCell(text))
+	}
+
+	return row, nil
+}
+
+// mkHTMLColDescs returns the descriptions of the columns
+func mkHTMLColDescs[T any](
+	cols *rptmaker.Cols[*prog, T], colIDs []rptmaker.ColID,
+) (
+	[]htmlColDesc, error,
+) {
+	descs := make([]htmlColDesc, 0, len(colIDs))
+
+	for _, cid := range colIDs {
+		ci, err := cols.GetReportableColInfo(cid)
+		if err != nil {
+			return nil, err
+		}
+
+		descs = append(descs, htmlColDesc{
+			ID:      cid,
+			Heading: strings.Join(ci.Headings(), " "),
+			Desc:    ci.FullDesc(),
+		})
+	}
+
+	return descs, nil
+}
+
+// mkHTMLModList returns the titled list of modules, with each module
+// linked to its details if they are in the report
+func (prog *prog) mkHTMLModList(
+	title string, mods []*modInfo, shown map[string]bool,
+) htmlModList {
+	ml := htmlModList{Title: title}
+
+	for _, mi := range mods {
+		link := htmlModLink{Name: strings.TrimPrefix(mi.Name, prog.stripPrefix)}
+		if shown[mi.Name] {
+			link.Anchor = htmlAnchor(mi)
+		}
+
+		ml.Mods = append(ml.Mods, link)
+	}
+
+	return ml
+}
+
+// mkHTMLReport populates the values needed to generate the HTML report
+func (prog *prog) mkHTMLReport() (htmlReport, error) {
+	rpt := htmlReport{Title: "gomodlayers: module report"}
+
+	if prog.showIntro {
+		rpt.Intro = modReportIntro
+	}
+
+	cols, err := mkHTMLColDescs(prog.cols, prog.columnsToShow)
+	if err != nil {
+		return rpt, err
+	}
+
+	cts, err := newColTexters(prog, prog.cols, prog.columnsToShow)
+	if err != nil {
+		return rpt, err
+	}
+
+	pkgCols, err := mkHTMLColDescs(prog.pkgCols, prog.pkgColumnsToShow)
+	if err != nil {
+		return rpt, err
+	}
+
+	pkgCts, err := newColTexters(prog, prog.pkgCols, prog.pkgColumnsToShow)
+	if err != nil {
+		return rpt, err
+	}
+
+	rpt.Cols, rpt.PkgCols, rpt.ColCount = cols, pkgCols, len(cols)
+
+	shown := map[string]bool{}
+	for _, mi := range prog.mInfo {
+		shown[mi.Name] = true
+	}
+
+	// the modules are grouped by level, keeping the sort order within
+	// each level
+	mods := slices.Clone(prog.mInfo)
+	slices.SortStableFunc(mods, func(a, b *modInfo) int {
+		return cmp.Compare(a.Level, b.Level)
+	})
+
+	for _, mi := range mods {
+		if len(rpt.Levels) == 0 || rpt.Levels[len(rpt.Levels)-1].Level != mi.Level {
+			rpt.Levels = append(rpt.Levels, htmlLevel{Level: mi.Level})
+		}
+
+		row, err := mkHTMLRow(cts, mi)
+		if err != nil {
+			return rpt, err
+		}
+
+		for i := range row {
+			if cols[i].ID == ColName {
+				row[i].Link = htmlAnchor(mi)
+			}
+		}
+
+		lvl := &rpt.Levels[len(rpt.Levels)-1]
+		lvl.Rows = append(lvl.Rows, row)
+
+		hm := htmlModule{
+			Name:   strings.TrimPrefix(mi.Name, prog.stripPrefix),
+			Anchor: htmlAnchor(mi),
+			Level:  mi.Level,
+			Lists: []htmlModList{
+				prog.mkHTMLModList("Uses directly", mi.DirectReqs, shown),
+				prog.mkHTMLModList("Uses indirectly", mi.IndirectReqs, shown),
+				prog.mkHTMLModList("Used by directly",
+					mi.ReqdByDirectly, shown),
+				prog.mkHTMLModList("Used by indirectly",
+					mi.ReqdByIndirectly, shown),
+			},
+		}
+
+		for _, pi := range mi.sortedPackages() {
+			pkgRow, err := mkHTMLRow(pkgCts, pi)
+			if err != nil {
+				return rpt, err
+			}
+
+			hm.Packages = append(hm.Packages, pkgRow)
+		}
+
+		rpt.Modules = append(rpt.Modules, hm)
+	}
+
+	rows := [][]htmlCell{}
+	for _, lvl := range rpt.Levels {
+		rows = append(rows, lvl.Rows...)
+	}
+
+	setNumericCols(rpt.Cols, rows)
+
+	pkgRows := [][]htmlCell{}
+	for _, hm := range rpt.Modules {
+		pkgRows = append(pkgRows, hm.Packages...)
+	}
+
+	setNumericCols(rpt.PkgCols, pkgRows)
+
+	return rpt, nil
+}
+
+// reportHTML prints the module report as a single, self-contained HTML
+// page. The page shows the modules in a table, grouped by level, which can
+// be sorted by any column. Each module name links to the details of the
+// module, showing the modules it uses and is used by and its packages.
+func (prog *prog) reportHTML(w io.Writer) {
+	// recreate the cols with the prog value post param parsing
+	prog.cols = prog.populateCols()
+	prog.setMaxPkgNameLens()
+	prog.pkgCols = prog.populatePkgCols()
+
+	err := prog.sortModInfo()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't sort the report:", err)
+		prog.setExitStatus(1)
+
+		return
+	}
+
+	rpt, err := prog.mkHTMLReport()
+	if err == nil {
+		err = htmlTemplate.Execute(w, rpt)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't print the HTML report:", err)
+		prog.setExitStatus(1)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReportHTML(t *testing.T) {
+	prog := newProg()
+	prog.mm = testModMapABCD(t)
+	prog.stripPrefix = "example.com/"
+	prog.columnsToShow = []rptmaker.ColID{ColLevel, ColName, ColUseCountTotal}
+	prog.populateModInfo()
+
+	var buf bytes.Buffer
+
+	prog.reportHTML(&buf)
+
+	testhelper.DiffInt(t, "html", "exit status", prog.exitStatus, 0)
+
+	out := buf.String()
+
+	for _, exp := range []string{
+		`<th colspan="3">Level 0</th>`,
+		`<td class="num" data-sort="0">0</td>`,
+		`<td data-sort="A"><a href="#module-example.com%2fA">A</a></td>`,
+		`<section class="module" id="module-example.com/B">`,
+		`<li><a href="#module-example.com%2fA">A</a></li>`,
+		`<li>X (external)</li>`,
+		`<dt>use-count</dt>`,
+		`<th class="sortable" title="level"` + "\n" + ` data-type="num"`,
+		`<th class="sortable" title="name"` + "\n" + ` data-type="str"`,
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("the HTML report does not contain: %s", exp)
+		}
+	}
+}
+
+func TestSetNumericCols(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals       []string
+		expNumeric bool
+	}{
+		{
+			ID:         testhelper.MkID("numbers"),
+			vals:       []string{"1", "2.5", "-3"},
+			expNumeric: true,
+		},
+		{
+			ID:         testhelper.MkID("numbers and blanks"),
+			vals:       []string{"1", "", "3"},
+			expNumeric: true,
+		},
+		{
+			ID:   testhelper.MkID("numbers and text"),
+			vals: []string{"1", "two", "3"},
+		},
+		{
+			ID:   testhelper.MkID("all blank"),
+			vals: []string{"", ""},
+		},
+	}
+
+	for _, tc := range testCases {
+		cols := []htmlColDesc{{ID: "col"}}
+		rows := [][]htmlCell{}
+
+		for _, v := range tc.vals {
+			rows = append(rows, []htmlCell{mkHTMLCell(v)})
+		}
+
+		setNumericCols(cols, rows)
+
+		testhelper.DiffBool(t, tc.IDStr(), "numeric column",
+			cols[0].Numeric, tc.expNumeric)
+
+		for i, row := range rows {
+			testhelper.DiffBool(t, tc.IDStr(),
+				"numeric cell "+strconv.Itoa(i),
+				row[0].Numeric, tc.expNumeric)
+		}
+	}
+}
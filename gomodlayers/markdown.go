@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+)
+
+// mdCellReplacer escapes the characters which would break a Markdown table
+// cell or be taken as HTML and replaces newlines with HTML line breaks. A
+// backslash is escaped so that it cannot escape the character following it.
+var mdCellReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\n", "<br>",
+)
+
+// mdCell returns the text formatted for use in a Markdown table cell.
+// Multi-line text is shown with HTML line breaks between the lines and
+// any characters which would break the table are escaped.
+func mdCell(text string) string {
+	return mdCellReplacer.Replace(text)
+}
+
+// mdText returns the text with any newlines replaced by spaces so that it
+// can be shown as a single Markdown paragraph or list item
+func mdText(s string) string {
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// writeMarkdownIntro writes the intro text as a paragraph followed by a list
+// giving the description of each of the columns
+func writeMarkdownIntro[T any](
+	w io.Writer,
+	intro string, cols *rptmaker.Cols[*prog, T], colIDs []rptmaker.ColID,
+) error {
+	fmt.Fprintln(w, mdText(intro))
+	fmt.Fprintln(w)
+
+	for _, cid := range colIDs {
+		ci, err := cols.GetReportableColInfo(cid)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "- `%s`: %s\n", cid, mdText(ci.FullDesc()))
+	}
+
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// writeMarkdownTable writes the values as a GitHub-flavoured Markdown table
+// with one row per value and one column for each of the column IDs
+func writeMarkdownTable[T any](
+	w io.Writer, p *prog,
+	cols *rptmaker.Cols[*prog, T], colIDs []rptmaker.ColID, vals []T,
+) error {
+	cts, err := newColTexters(p, cols, colIDs)
+	if err != nil {
+		return err
+	}
+
+	headings := make([]string, 0, len(colIDs))
+	underlines := make([]string, 0, len(colIDs))
+
+	for _, cid := range colIDs {
+		ci, err := cols.GetReportableColInfo(cid)
+		if err != nil {
+			return err
+		}
+
+		headings = append(headings, mdCell(strings.Join(ci.Headings(), " ")))
+		underlines = append(underlines, "---")
+	}
+
+	fmt.Fprintln(w, "| "+strings.Join(headings, " | ")+" |")
+	fmt.Fprintln(w, "| "+strings.Join(underlines, " | ")+" |")
+
+	for _, v := range vals {
+		cells := make([]string, 0, len(cts))
+		for _, ct := range cts {
+			text, err := ct.text(v)
+			if err != nil {
+				return err
+			}
+
+			cells = append(cells, mdCell(text))
+		}
+
+		fmt.Fprintln(w, "| "+strings.Join(cells, " | ")+" |")
+	}
+
+	return nil
+}
+
+// reportMarkdown prints the module report as a Markdown table, preceded by
+// the introduction if it is to be shown.
+func (prog *prog) reportMarkdown(w io.Writer) {
+	// recreate the cols with the prog value post param parsing
+	prog.cols = prog.populateCols()
+
+	err := prog.sortModInfo()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't sort the report:", err)
+		prog.setExitStatus(1)
+
+		return
+	}
+
+	if prog.showIntro {
+		err = writeMarkdownIntro(w, modReportIntro, prog.cols,
+			prog.columnsToShow)
+	}
+
+	if err == nil {
+		err = writeMarkdownTable(w, prog, prog.cols, prog.columnsToShow,
+			prog.mInfo)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't print the report:", err)
+		prog.setExitStatus(1)
+	}
+}
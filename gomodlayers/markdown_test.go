@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestMdCell(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		val    string
+		expVal string
+	}{
+		{ID: testhelper.MkID("plain"), val: "42", expVal: "42"},
+		{ID: testhelper.MkID("empty"), val: "", expVal: ""},
+		{ID: testhelper.MkID("multi-line"), val: "a\nb", expVal: "a<br>b"},
+		{ID: testhelper.MkID("pipe"), val: "a|b", expVal: `a\|b`},
+		{ID: testhelper.MkID("backslash"), val: `a\b`, expVal: `a\\b`},
+		{
+			ID:     testhelper.MkID("backslash before a pipe"),
+			val:    `a\|b`,
+			expVal: `a\\\|b`,
+		},
+		{
+			ID:     testhelper.MkID("HTML"),
+			val:    "<b>a & b</b>",
+			expVal: "&lt;b&gt;a &amp; b&lt;/b&gt;",
+		},
+		{
+			ID:     testhelper.MkID("HTML and multi-line"),
+			val:    "a<\n>b",
+			expVal: "a&lt;<br>&gt;b",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "cell", mdCell(tc.val), tc.expVal)
+	}
+}
+
+func TestReportMarkdown(t *testing.T) {
+	prog := newProg()
+	prog.mm = testModMapABCD(t)
+	prog.stripPrefix = "example.com/"
+	prog.showIntro = false
+	prog.columnsToShow = []rptmaker.ColID{
+		ColLevel, ColName, ColUsedBy, ColInstability,
+	}
+	prog.populateModInfo()
+
+	var buf bytes.Buffer
+
+	prog.reportMarkdown(&buf)
+
+	expOut := "| Level | Module name | Used By | I |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| 0 | A | B<br><br>** Indirect **<br>C<br>D | 0.00 |\n" +
+		"| 1 | B | C<br><br>** Indirect **<br>D | 0.50 |\n" +
+		"| 2 | C | D | 0.67 |\n" +
+		"| 3 | D |  | 1.00 |\n"
+
+	testhelper.DiffString(t, "markdown", "output", buf.String(), expOut)
+	testhelper.DiffInt(t, "markdown", "exit status", prog.exitStatus, 0)
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"errors"
+	"go/build"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// licenceNames gives the names of the files, ignoring case and any
+// extension, which are taken to hold the licence of a module
+var licenceNames = []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"}
+
+// modCacheInfo records the information about an external module found in
+// the local module cache
+type modCacheInfo struct {
+	Version     string
+	GoModFile   string
+	Dir         string
+	LicenceFile string
+	Size        int
+}
+
+// cacheVersion returns the version of the module found in the module cache
+// or the empty string if it was not found
+func (mi *modInfo) cacheVersion() string {
+	if mi.Cache == nil {
+		return ""
+	}
+
+	return mi.Cache.Version
+}
+
+// licenceFile returns the name of the licence file of the module found in
+// the module cache or the empty string if there is none
+func (mi *modInfo) licenceFile() string {
+	if mi.Cache == nil {
+		return ""
+	}
+
+	return mi.Cache.LicenceFile
+}
+
+// cacheSize returns the size of the module found in the module cache or
+// zero if it was not found
+func (mi *modInfo) cacheSize() int {
+	if mi.Cache == nil {
+		return 0
+	}
+
+	return mi.Cache.Size
+}
+
+// defaultModCacheDir returns the directory of the module cache as the go
+// command would find it, from the GOMODCACHE environment variable or else
+// the first entry in GOPATH.
+func defaultModCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+
+	gopath := filepath.SplitList(build.Default.GOPATH)
+	if len(gopath) == 0 {
+		return ""
+	}
+
+	return filepath.Join(gopath[0], "pkg", "mod")
+}
+
+// modCachePaths returns the name of the cached go.mod file and the name of
+// the directory holding the extracted source for the given version of the
+// module.
+func modCachePaths(cacheDir, name, version string) (string, string, error) {
+	escPath, err := module.EscapePath(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	escVer, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", "", err
+	}
+
+	return filepath.Join(cacheDir, "cache", "download", escPath,
+			"@v", escVer+".mod"),
+		filepath.Join(cacheDir, escPath+"@"+escVer),
+		nil
+}
+
+// findLicenceFile returns the name of the licence file in the directory or
+// the empty string if there is none. If there are several the first, in
+// name order, is returned.
+func findLicenceFile(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		base := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		if slices.Contains(licenceNames, strings.ToUpper(base)) {
+			return e.Name()
+		}
+	}
+
+	return ""
+}
+
+// dirSize returns the total size in bytes of the regular files in the
+// directory and its sub-directories
+func dirSize(dir string) (int, error) {
+	size := 0
+
+	err := filepath.WalkDir(dir,
+		func(_ string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += int(info.Size())
+
+			return nil
+		})
+
+	return size, err
+}
+
+// setCacheInfo records the contents of the external module's go.mod file,
+// as found in the module cache, against the module. The requirements are
+// added to the module map except for those on modules in the collection:
+// these refer to published versions of those modules rather than the ones
+// being examined.
+func (mi *modInfo) setCacheInfo(mm modMap, modFile *modfile.File) {
+	mi.Deprecated = modFile.Module.Deprecated
+
+	if modFile.Go != nil {
+		mi.GoVersion = modFile.Go.Version
+	}
+
+	if modFile.Toolchain != nil {
+		mi.Toolchain = modFile.Toolchain.Name
+	}
+
+	for _, req := range modFile.Require {
+		if rmi, ok := mm[req.Mod.Path]; ok && rmi.Loc != nil {
+			continue
+		}
+
+		mi.addReqs(mm, req)
+	}
+}
+
+// clearReqs removes the requirements of the module, taking the module out
+// of the lists of users of the modules that it required
+func (mi *modInfo) clearReqs() {
+	isMI := func(m *modInfo) bool { return m == mi }
+
+	for _, ri := range mi.Reqs {
+		ri.Mod.ReqdByDirectly = slices.DeleteFunc(ri.Mod.ReqdByDirectly, isMI)
+		ri.Mod.ReqdByIndirectly = slices.DeleteFunc(ri.Mod.ReqdByIndirectly,
+			isMI)
+	}
+
+	clear(mi.Reqs)
+	mi.DirectReqs = nil
+	mi.IndirectReqs = nil
+}
+
+// clearCacheInfo removes any details recorded from the module cache for a
+// previously looked up version of the module
+func (mi *modInfo) clearCacheInfo() {
+	if mi.Cache == nil {
+		return
+	}
+
+	mi.clearReqs()
+	mi.Deprecated = ""
+	mi.GoVersion = ""
+	mi.Toolchain = ""
+	mi.Cache = nil
+}
+
+// resolveFromCache looks up the given version of the external module in
+// the module cache and records the details found. Any details recorded for
+// a different version are removed first. It returns a nil error if the
+// module is not in the cache.
+func (mi *modInfo) resolveFromCache(
+	mm modMap, cacheDir, version string,
+) error {
+	mi.clearCacheInfo()
+
+	goModFile, dir, err := modCachePaths(cacheDir, mi.Name, version)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(goModFile) //nolint:gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	modFile, err := modfile.ParseLax(goModFile, contents, nil)
+	if err != nil {
+		return err
+	}
+
+	mi.setCacheInfo(mm, modFile)
+
+	mi.Cache = &modCacheInfo{
+		Version:   version,
+		GoModFile: goModFile,
+	}
+
+	if size, err := dirSize(dir); err == nil {
+		mi.Cache.Dir = dir
+		mi.Cache.Size = size
+		mi.Cache.LicenceFile = findLicenceFile(dir)
+	}
+
+	return nil
+}
+
+// resolveExternal looks up each external module in the module cache. The
+// version looked up is the highest version required by any module. The
+// requirements of the modules found are added to the module map and these
+// are looked up in turn. If a module found later requires a higher version
+// of a module already looked up then that version is looked up in its
+// place. This is repeated until no new module versions are needed. Any
+// external modules which are then no longer required are removed. The cache
+// is only read, it is never updated, and no network access is needed. Any
+// errors are returned in the error map.
+func (mm modMap) resolveExternal(cacheDir string) *errutil.ErrMap {
+	errMap := errutil.NewErrMap()
+	tried := map[string]bool{}
+
+	for {
+		versions := map[*modInfo]string{}
+
+		for _, mi := range mm {
+			if mi.Loc != nil {
+				continue
+			}
+
+			version := mm.requiredVersion(mi.Name)
+			if version != "" && !tried[mi.Name+"@"+version] {
+				versions[mi] = version
+			}
+		}
+
+		if len(versions) == 0 {
+			break
+		}
+
+		for _, mi := range slices.SortedFunc(maps.Keys(versions),
+			cmpModNames) {
+			version := versions[mi]
+			tried[mi.Name+"@"+version] = true
+
+			if err := mi.resolveFromCache(mm, cacheDir, version); err != nil {
+				errMap.AddError(mi.Name, err)
+			}
+		}
+	}
+
+	mm.removeUnusedExternal()
+	mm.sortReqdByNames()
+
+	return errMap
+}
+
+// removeUnusedExternal removes the external modules which are not required
+// by any module. This is repeated until all such modules are removed.
+func (mm modMap) removeUnusedExternal() {
+	for {
+		removed := false
+
+		for name, mi := range mm {
+			if mi.Loc == nil &&
+				len(mi.ReqdByDirectly) == 0 && len(mi.ReqdByIndirectly) == 0 {
+				mi.clearReqs()
+				delete(mm, name)
+
+				removed = true
+			}
+		}
+
+		if !removed {
+			return
+		}
+	}
+}
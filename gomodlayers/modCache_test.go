@@ -0,0 +1,209 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// writeTestFile writes the contents to the named file, creating any
+// directories needed. Any errors are reported as fatal errors.
+func writeTestFile(t *testing.T, fname, contents string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(fname), 0o700); err != nil {
+		t.Fatalf("cannot make the directory for %s: %s", fname, err)
+	}
+
+	if err := os.WriteFile(fname, []byte(contents), 0o600); err != nil {
+		t.Fatalf("cannot write %s: %s", fname, err)
+	}
+}
+
+func TestResolveExternal(t *testing.T) {
+	cacheDir := t.TempDir()
+	dlDir := filepath.Join(cacheDir, "cache", "download")
+
+	writeTestFile(t, filepath.Join(dlDir, "example.com", "!x", "@v",
+		"v1.1.0.mod"),
+		"module example.com/X\n"+
+			"go 1.22\n"+
+			"require (\n"+
+			"\texample.com/Y v0.5.0\n"+
+			"\texample.com/A v0.1.0\n"+
+			")\n")
+	writeTestFile(t, filepath.Join(cacheDir, "example.com", "!x@v1.1.0",
+		"LICENSE.md"), "licence\n")
+	writeTestFile(t, filepath.Join(cacheDir, "example.com", "!x@v1.1.0",
+		"x.go"), "package x\n")
+	writeTestFile(t, filepath.Join(dlDir, "example.com", "!y", "@v",
+		"v0.5.0.mod"),
+		"module example.com/Y\n"+
+			"require example.com/Z v0.2.0\n")
+
+	mm := mkTestModMap(t,
+		"module example.com/A\n",
+		"module example.com/B\n"+
+			"require (\n"+
+			"\texample.com/A v1.0.0\n"+
+			"\texample.com/X v1.0.0\n"+
+			")\n",
+		"module example.com/C\n"+
+			"require (\n"+
+			"\texample.com/X v1.1.0\n"+
+			"\texample.com/W v1.0.0\n"+
+			")\n",
+	)
+
+	errMap := mm.resolveExternal(cacheDir)
+	if errMap.HasErrors() {
+		t.Fatal("unexpected errors resolving the external modules")
+	}
+
+	x := mm["example.com/X"]
+	if x.Cache == nil {
+		t.Fatal("example.com/X was not found in the module cache")
+	}
+
+	testhelper.DiffString(t, "X", "cache version", x.cacheVersion(), "v1.1.0")
+	testhelper.DiffString(t, "X", "go version", x.GoVersion, "1.22")
+	testhelper.DiffString(t, "X", "licence file", x.licenceFile(),
+		"LICENSE.md")
+	testhelper.DiffInt(t, "X", "size", x.cacheSize(),
+		len("licence\n")+len("package x\n"))
+
+	reqNames := []string{}
+	for _, rmi := range x.DirectReqs {
+		reqNames = append(reqNames, rmi.Name)
+	}
+
+	testhelper.DiffStringSlice(t, "X", "direct requirements",
+		reqNames, []string{"example.com/Y"})
+	testhelper.DiffInt(t, "A", "direct users",
+		len(mm["example.com/A"].ReqdByDirectly), 1)
+
+	y := mm["example.com/Y"]
+	testhelper.DiffString(t, "Y", "cache version", y.cacheVersion(), "v0.5.0")
+	testhelper.DiffString(t, "Y", "licence file", y.licenceFile(), "")
+	testhelper.DiffInt(t, "Y", "size", y.cacheSize(), 0)
+
+	for _, name := range []string{"example.com/W", "example.com/Z"} {
+		mi, ok := mm[name]
+		if !ok {
+			t.Errorf("%s is not in the module map", name)
+			continue
+		}
+
+		testhelper.DiffBool(t, name, "found", mi.Cache != nil, false)
+	}
+
+	mm.calcLevels(false)
+	testhelper.DiffInt(t, "internal levels only", "level of C",
+		mm["example.com/C"].Level, 0)
+
+	mm.calcLevels(true)
+
+	for _, tc := range []struct {
+		name     string
+		expLevel int
+	}{
+		{name: "example.com/Z", expLevel: 0},
+		{name: "example.com/Y", expLevel: 1},
+		{name: "example.com/X", expLevel: 2},
+		{name: "example.com/C", expLevel: 3},
+	} {
+		testhelper.DiffInt(t, "external levels", "level of "+tc.name,
+			mm[tc.name].Level, tc.expLevel)
+	}
+}
+
+func TestResolveExternalRaisedVersion(t *testing.T) {
+	cacheDir := t.TempDir()
+	dlDir := filepath.Join(cacheDir, "cache", "download", "example.com")
+
+	writeTestFile(t, filepath.Join(dlDir, "!x", "@v", "v1.0.0.mod"),
+		"module example.com/X\n"+
+			"require (\n"+
+			"\texample.com/W v0.1.0\n"+
+			"\texample.com/Z v0.1.0\n"+
+			")\n")
+	writeTestFile(t, filepath.Join(dlDir, "!x", "@v", "v1.1.0.mod"),
+		"module example.com/X\n"+
+			"require example.com/Z v0.2.0\n")
+	writeTestFile(t, filepath.Join(dlDir, "!y", "@v", "v1.0.0.mod"),
+		"module example.com/Y\n"+
+			"require example.com/X v1.1.0\n")
+	writeTestFile(t, filepath.Join(dlDir, "!w", "@v", "v0.1.0.mod"),
+		"module example.com/W\n")
+	writeTestFile(t, filepath.Join(dlDir, "!z", "@v", "v0.1.0.mod"),
+		"module example.com/Z\n")
+	writeTestFile(t, filepath.Join(dlDir, "!z", "@v", "v0.2.0.mod"),
+		"module example.com/Z\n"+
+			"go 1.23\n")
+
+	mm := mkTestModMap(t,
+		"module example.com/A\n"+
+			"require (\n"+
+			"\texample.com/X v1.0.0\n"+
+			"\texample.com/Y v1.0.0\n"+
+			")\n",
+	)
+
+	if errMap := mm.resolveExternal(cacheDir); errMap.HasErrors() {
+		t.Fatal("unexpected errors resolving the external modules")
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		name       string
+		expVersion string
+		expReqs    []string
+	}{
+		{
+			ID:         testhelper.MkID("raised by a later module"),
+			name:       "example.com/X",
+			expVersion: "v1.1.0",
+			expReqs:    []string{"example.com/Z"},
+		},
+		{
+			ID:         testhelper.MkID("raised by a re-resolved module"),
+			name:       "example.com/Z",
+			expVersion: "v0.2.0",
+			expReqs:    []string{},
+		},
+		{
+			ID:         testhelper.MkID("the later module"),
+			name:       "example.com/Y",
+			expVersion: "v1.0.0",
+			expReqs:    []string{"example.com/X"},
+		},
+	}
+
+	for _, tc := range testCases {
+		mi, ok := mm[tc.name]
+		if !ok {
+			t.Errorf("%s: %s is not in the module map", tc.IDStr(), tc.name)
+			continue
+		}
+
+		reqNames := []string{}
+		for _, rmi := range mi.DirectReqs {
+			reqNames = append(reqNames, rmi.Name)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "cache version",
+			mi.cacheVersion(), tc.expVersion)
+		testhelper.DiffStringSlice(t, tc.IDStr(), "direct requirements",
+			reqNames, tc.expReqs)
+	}
+
+	testhelper.DiffString(t, "Z", "go version",
+		mm["example.com/Z"].GoVersion, "1.23")
+
+	if _, ok := mm["example.com/W"]; ok {
+		t.Error("example.com/W, required only by X v1.0.0," +
+			" is still in the module map")
+	}
+}
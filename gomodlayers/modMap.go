@@ -14,11 +14,20 @@ type modMap map[string]*modInfo
 
 // populate fills the modMap with the module information from the given
 // files. Note that the 'file' names can be directory names in which case the
-// name of the Go module file is added.
-func (mm modMap) populate(fNames []string) *errutil.ErrMap {
+// name of the Go module file is added. The packages in each module are
+// scanned according to the scan options.
+//
+// Any errors found while parsing the go.mod files are returned in the first
+// error map. Errors found while scanning the packages in each module are
+// returned in the second error map and are also recorded against the
+// module.
+func (mm modMap) populate(
+	fNames []string, opts pkgScanOpts,
+) (*errutil.ErrMap, *errutil.ErrMap) {
 	const goMod = "go.mod"
 
 	errMap := errutil.NewErrMap()
+	scanErrMap := errutil.NewErrMap()
 
 	for _, fname := range fNames {
 		if !strings.HasSuffix(fname, goMod) {
@@ -39,12 +48,12 @@ func (mm modMap) populate(fNames []string) *errutil.ErrMap {
 			continue
 		}
 
-		mi.getPackageInfo(filepath.Dir(fname))
+		mi.getPackageInfo(filepath.Dir(fname), opts, scanErrMap)
 	}
 
 	mm.sortReqdByNames()
 
-	return errMap
+	return errMap, scanErrMap
 }
 
 // sortReqdByNames sorts the cross reference entries for each modInfo
@@ -70,8 +79,10 @@ func (mm modMap) findMaxNameLen() int {
 // keeps on doing this until it has made no further changes; this should be
 // sufficient as Go does not permit loops in module requirements but to cope
 // with bugs in module specs we abort if the max level observed is greater
-// than the total number of modules being considered.
-func (mm modMap) calcLevels() {
+// than the total number of modules being considered. If inclExternal is
+// set then the levels of the external modules are calculated and taken
+// into account.
+func (mm modMap) calcLevels(inclExternal bool) {
 	levelChange := true
 	maxLevel := 0
 
@@ -79,7 +90,7 @@ func (mm modMap) calcLevels() {
 		levelChange = false
 
 		for _, mi := range mm {
-			if mi.calcLevel() {
+			if mi.calcLevel(inclExternal) {
 				levelChange = true
 				maxLevel = max(mi.Level, maxLevel)
 			}
@@ -95,3 +106,20 @@ func (mm modMap) calcReqCount() {
 		mi.setReqCounts()
 	}
 }
+
+// calcBlastRadius will calculate, for each module, the total lines of code
+// and the total number of packages in all the modules which depend on it,
+// either directly or indirectly.
+func (mm modMap) calcBlastRadius() {
+	for _, mi := range mm {
+		for name := range reachable([]*modInfo{mi}, usedBy, noDepthLimit) {
+			if name == mi.Name {
+				continue
+			}
+
+			dmi := mm[name]
+			mi.DependantsLoC += dmi.LinesOfCode
+			mi.DependantsPkgs += len(dmi.Packages)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestCalcBlastRadius(t *testing.T) {
+	mm := testModMapABCD(t)
+
+	for name, loc := range map[string]int{
+		"example.com/A": 1,
+		"example.com/B": 10,
+		"example.com/C": 100,
+		"example.com/D": 1000,
+	} {
+		mm[name].LinesOfCode = loc
+		mm[name].Packages[name] = &PkgInfo{}
+	}
+
+	mm.calcBlastRadius()
+
+	for name, exp := range map[string]struct{ loc, pkgs int }{
+		"example.com/A": {loc: 1110, pkgs: 3},
+		"example.com/B": {loc: 1100, pkgs: 2},
+		"example.com/C": {loc: 1000, pkgs: 1},
+		"example.com/D": {loc: 0, pkgs: 0},
+		"example.com/X": {loc: 1100, pkgs: 2},
+	} {
+		testhelper.DiffInt(t, name, "dependants LoC",
+			mm[name].DependantsLoC, exp.loc)
+		testhelper.DiffInt(t, name, "dependants packages",
+			mm[name].DependantsPkgs, exp.pkgs)
+	}
+}
+
+func TestPopulateErrors(t *testing.T) {
+	dir := t.TempDir()
+	goodDir := filepath.Join(dir, "good")
+	badDir := filepath.Join(dir, "bad")
+
+	writeTestFile(t, filepath.Join(goodDir, "go.mod"), "module example.com/A\n")
+	writeTestFile(t, filepath.Join(goodDir, "a.go"), "package a\n")
+	writeTestFile(t, filepath.Join(badDir, "go.mod"), "module example.com/B\n")
+	writeTestFile(t, filepath.Join(badDir, "b.go"), "package b\nfunc {\n")
+
+	testCases := []struct {
+		testhelper.ID
+		fNames         []string
+		expErrCount    int
+		expScanErrs    bool
+		expModuleCount int
+	}{
+		{
+			ID:             testhelper.MkID("no errors"),
+			fNames:         []string{goodDir},
+			expModuleCount: 1,
+		},
+		{
+			ID:             testhelper.MkID("scan error only"),
+			fNames:         []string{goodDir, badDir},
+			expScanErrs:    true,
+			expModuleCount: 2,
+		},
+		{
+			ID:             testhelper.MkID("missing go.mod file"),
+			fNames:         []string{goodDir, filepath.Join(dir, "none")},
+			expErrCount:    1,
+			expModuleCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		mm := modMap{}
+		errMap, scanErrMap := mm.populate(tc.fNames,
+			pkgScanOpts{allPlatforms: true})
+
+		errCount, _ := errMap.CountErrors()
+		testhelper.DiffInt(t, tc.IDStr(), "error count",
+			errCount, tc.expErrCount)
+		testhelper.DiffBool(t, tc.IDStr(), "scan errors",
+			scanErrMap.HasErrors(), tc.expScanErrs)
+		testhelper.DiffInt(t, tc.IDStr(), "module count",
+			len(mm), tc.expModuleCount)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// modPatterns records a collection of patterns against which module names
+// can be matched. A name matches if it matches any of the patterns.
+type modPatterns struct {
+	regexps []*regexp.Regexp
+	globs   []string
+}
+
+// addRegexps compiles each of the regular expressions and adds them to the
+// patterns. Each regular expression is anchored at both ends so that it
+// must match the whole of the module name. It returns an error if any of the
+// regular expressions cannot be compiled.
+func (mp *modPatterns) addRegexps(res map[string]bool) error {
+	for _, re := range slices.Sorted(maps.Keys(res)) {
+		if !res[re] {
+			continue
+		}
+
+		cre, err := regexp.Compile("^(?:" + re + ")$")
+		if err != nil {
+			return fmt.Errorf("bad module name regular expression %q: %w",
+				re, err)
+		}
+
+		mp.regexps = append(mp.regexps, cre)
+	}
+
+	return nil
+}
+
+// addGlobs checks each of the glob patterns and adds them to the patterns.
+// It returns an error if any of the patterns is malformed.
+func (mp *modPatterns) addGlobs(globs map[string]bool) error {
+	for _, g := range slices.Sorted(maps.Keys(globs)) {
+		if !globs[g] {
+			continue
+		}
+
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("bad module name glob pattern %q: %w", g, err)
+		}
+
+		mp.globs = append(mp.globs, g)
+	}
+
+	return nil
+}
+
+// isEmpty returns true if there are no patterns
+func (mp modPatterns) isEmpty() bool {
+	return len(mp.regexps) == 0 && len(mp.globs) == 0
+}
+
+// matches returns true if the name matches any of the patterns
+func (mp modPatterns) matches(name string) bool {
+	for _, re := range mp.regexps {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	for _, g := range mp.globs {
+		if ok, _ := path.Match(g, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestModPatterns(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		regexps map[string]bool
+		globs   map[string]bool
+		matches map[string]bool
+	}{
+		{
+			ID: testhelper.MkID("regexp"),
+			regexps: map[string]bool{
+				"github.com/acme/(auth|billing).*": true,
+			},
+			matches: map[string]bool{
+				"github.com/acme/auth":          true,
+				"github.com/acme/billing/v2":    true,
+				"github.com/acme/shipping":      false,
+				"example.com/github.com/acme/a": false,
+			},
+		},
+		{
+			ID: testhelper.MkID("glob"),
+			globs: map[string]bool{
+				"github.com/acme/*": true,
+			},
+			matches: map[string]bool{
+				"github.com/acme/auth":       true,
+				"github.com/acme/billing/v2": false,
+				"github.com/other/auth":      false,
+			},
+		},
+		{
+			ID: testhelper.MkID("disabled entries"),
+			regexps: map[string]bool{
+				".*": false,
+			},
+			globs: map[string]bool{
+				"*": false,
+			},
+			matches: map[string]bool{
+				"anything": false,
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad regexp"),
+			ExpErr: testhelper.MkExpErr("bad module name regular expression"),
+			regexps: map[string]bool{
+				"(": true,
+			},
+		},
+		{
+			ID:     testhelper.MkID("bad glob"),
+			ExpErr: testhelper.MkExpErr("bad module name glob pattern"),
+			globs: map[string]bool{
+				"[": true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var mp modPatterns
+
+		err := mp.addRegexps(tc.regexps)
+		if err == nil {
+			err = mp.addGlobs(tc.globs)
+		}
+
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		for name, expMatch := range tc.matches {
+			testhelper.DiffBool(t, tc.IDStr(), "matches: "+name,
+				mp.matches(name), expMatch)
+		}
+	}
+}
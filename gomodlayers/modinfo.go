@@ -1,9 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"go/parser"
+	"go/scanner"
 	"go/token"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/nickwells/check.mod/v2/check"
 	"github.com/nickwells/dirsearch.mod/v2/dirsearch"
+	"github.com/nickwells/errutil.mod/errutil"
 	"github.com/nickwells/location.mod/location"
 
 	"golang.org/x/mod/modfile"
@@ -20,22 +24,43 @@ import (
 type modInfo struct {
 	Loc              *location.L
 	Name             string
+	Deprecated       string
+	GoVersion        string
+	Toolchain        string
 	DirectReqs       []*modInfo
 	IndirectReqs     []*modInfo
 	ReqCountInt      int
 	ReqCountExt      int
 	Level            int
 	LinesOfCode      int
+	TestLinesOfCode  int
+	GeneratedLoC     int
+	Lines            LineCounts
+	DependantsLoC    int
+	DependantsPkgs   int
+	API              APICounts
 	ReqdByDirectly   []*modInfo
 	ReqdByIndirectly []*modInfo
+	Reqs             map[string]*ReqInfo
 	Packages         map[string]*PkgInfo
+	ScanErrors       []error
+	ExcludedFiles    []string
+	Retracts         []*modfile.Retract
+	Excludes         []*modfile.Exclude
+	Tools            []*modfile.Tool
+	Godebugs         []*modfile.Godebug
+	Replaces         []*modfile.Replace
+	GoSumRead        bool
+	UnneededSums     []goSumEntry
+	Cache            *modCacheInfo
 }
 
-// newModInfo creates a new ModInfo with the name populated and the Packages
-// map initialised.
+// newModInfo creates a new ModInfo with the name populated and the Reqs and
+// Packages maps initialised.
 func newModInfo(name string) *modInfo {
 	return &modInfo{
 		Name:     name,
+		Reqs:     map[string]*ReqInfo{},
 		Packages: map[string]*PkgInfo{},
 	}
 }
@@ -62,11 +87,26 @@ func parseGoModFile(modules modMap, contents []byte, loc *location.L) (
 	}
 
 	mi := getModuleInfo(modules, modFile.Module.Mod.Path, loc)
+	mi.Deprecated = modFile.Module.Deprecated
+
+	if modFile.Go != nil {
+		mi.GoVersion = modFile.Go.Version
+	}
+
+	if modFile.Toolchain != nil {
+		mi.Toolchain = modFile.Toolchain.Name
+	}
 
 	for _, req := range modFile.Require {
-		mi.addReqs(modules, req.Mod.Path, req.Indirect)
+		mi.addReqs(modules, req)
 	}
 
+	mi.Retracts = modFile.Retract
+	mi.Excludes = modFile.Exclude
+	mi.Tools = modFile.Tool
+	mi.Godebugs = modFile.Godebug
+	mi.Replaces = modFile.Replace
+
 	return mi, nil
 }
 
@@ -97,19 +137,23 @@ func getModuleInfo(modules modMap, modName string, loc *location.L) *modInfo {
 	return nil
 }
 
-// addReqs expects to be passed a non-nil ModInfo and the parts
-// of a require line. It will find the corresponding module for the required
-// module and record that as a requirement of the module and also record that
-// this module requires the other module. If there is a problem it will
-// report it .
-func (mi *modInfo) addReqs(modules modMap, requires string, indirect bool) {
+// addReqs expects to be passed a non-nil ModInfo and a require line. It
+// will find the corresponding module for the required module and record
+// that as a requirement of the module and also record that this module
+// requires the other module. The details of the requirement are recorded in
+// the Reqs map.
+func (mi *modInfo) addReqs(modules modMap, req *modfile.Require) {
+	requires := req.Mod.Path
+
 	reqdMI, ok := modules[requires]
 	if !ok { // the required module is not yet known, so create a new one
 		reqdMI = newModInfo(requires)
 		modules[requires] = reqdMI
 	}
 
-	if indirect {
+	mi.Reqs[requires] = newReqInfo(reqdMI, req)
+
+	if req.Indirect {
 		reqdMI.ReqdByIndirectly = append(reqdMI.ReqdByIndirectly, mi)
 		mi.IndirectReqs = append(mi.IndirectReqs, reqdMI)
 
@@ -121,14 +165,15 @@ func (mi *modInfo) addReqs(modules modMap, requires string, indirect bool) {
 }
 
 // calcLevel sets the level of the module to one greater than the max level
-// of those modules that it requires. It will return true if the level has
-// been changed.
-func (mi *modInfo) calcLevel() bool {
+// of those modules that it requires. Modules outside the set of modules
+// being considered are ignored unless inclExternal is set. It will return
+// true if the level has been changed.
+func (mi *modInfo) calcLevel(inclExternal bool) bool {
 	levelChange := false
 
 	for _, rmi := range mi.DirectReqs {
 		if rmi.Level >= mi.Level &&
-			rmi.Loc != nil { // ignore modules not in set of considered modules
+			(rmi.Loc != nil || inclExternal) {
 			mi.Level = rmi.Level + 1
 			levelChange = true
 		}
@@ -151,9 +196,133 @@ func (mi *modInfo) setReqCounts() {
 	}
 }
 
+// untestedPkgCount returns the number of packages in the module which have
+// no tests
+func (mi *modInfo) untestedPkgCount() int {
+	count := 0
+
+	for _, pi := range mi.Packages {
+		if !pi.hasTests() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// testRatio returns the ratio of the lines of test code to the lines of
+// non-test code. A module with no non-test code has a ratio of 0.
+func (mi *modInfo) testRatio() float64 {
+	if mi.LinesOfCode == 0 {
+		return 0
+	}
+
+	return float64(mi.TestLinesOfCode) / float64(mi.LinesOfCode)
+}
+
+// commandCount returns the number of packages in the module which are
+// commands
+func (mi *modInfo) commandCount() int {
+	count := 0
+
+	for _, pi := range mi.Packages {
+		if pi.isCommand() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// libraryCount returns the number of packages in the module which are
+// libraries (not commands)
+func (mi *modInfo) libraryCount() int {
+	return len(mi.Packages) - mi.commandCount()
+}
+
+// commandNames returns the sorted names of the commands in the module
+func (mi *modInfo) commandNames() []string {
+	names := []string{}
+
+	for _, pi := range mi.Packages {
+		if pi.isCommand() {
+			names = append(names, pi.commandName())
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// sortedPackages returns the packages in the module sorted by import name
+func (mi *modInfo) sortedPackages() []*PkgInfo {
+	pkgs := slices.Collect(maps.Values(mi.Packages))
+	slices.SortFunc(pkgs, func(a, b *PkgInfo) int {
+		return strings.Compare(a.ImportName, b.ImportName)
+	})
+
+	return pkgs
+}
+
+// scanErrCategory returns the name of the error category under which any
+// errors found while scanning the module's packages are recorded.
+func (mi *modInfo) scanErrCategory() string {
+	return "scanning the packages of " + mi.Name
+}
+
+// addScanError records the error against the module and adds it to the
+// errMap. If the error is a list of errors from the Go parser then each
+// error in the list is added separately so that the position of every error
+// is reported.
+func (mi *modInfo) addScanError(errMap *errutil.ErrMap, err error) {
+	var errList scanner.ErrorList
+	if errors.As(err, &errList) {
+		for _, e := range errList {
+			mi.addScanError(errMap, e)
+		}
+
+		return
+	}
+
+	mi.ScanErrors = append(mi.ScanErrors, err)
+	errMap.AddError(mi.scanErrCategory(), err)
+}
+
+// pkgScanOpts holds the options controlling how the packages in a module
+// are scanned
+type pkgScanOpts struct {
+	// includeGenerated, if set, will cause generated files to be included
+	// in the package statistics. They are always counted separately.
+	includeGenerated bool
+
+	// goos, goarch and buildTags are used to evaluate the build
+	// constraints on each file. Files which would not be included in a
+	// build are excluded from the package statistics.
+	goos      string
+	goarch    string
+	buildTags []string
+
+	// allPlatforms, if set, will cause files to be included if they would
+	// be built for any GOOS and GOARCH. The goos and goarch values are
+	// ignored.
+	allPlatforms bool
+}
+
 // getPackageInfo will walk the directory tree from the directory given and
-// will gather statistics about the packages found.
-func (mi *modInfo) getPackageInfo(dirName string) {
+// will gather statistics about the packages found. Any errors found are
+// recorded against the module and added to the errMap; a Go file which
+// cannot be parsed is skipped.
+//
+// Files which would not be included in a build, according to the build
+// constraints and the scan options, are recorded as excluded and are not
+// included in the statistics. Generated files are counted separately and,
+// unless the scan options say otherwise, are not included in any of the
+// other statistics; a package whose only files are excluded generated files
+// is not recorded.
+func (mi *modInfo) getPackageInfo(
+	dirName string, opts pkgScanOpts, errMap *errutil.ErrMap,
+) {
 	dirName = filepath.Clean(dirName)
 
 	// Note that Go ignores files and directories whose name begins with '.'
@@ -174,20 +343,40 @@ func (mi *modInfo) getPackageInfo(dirName string) {
 		check.FileInfoName(check.StringHasSuffix[string](".go")))
 
 	if len(errs) != 0 {
-		fmt.Println("Errors found while finding the package Go files")
-
 		for _, err := range errs {
-			fmt.Println("\t", err)
+			mi.addScanError(errMap, err)
 		}
 
 		return
 	}
 
 	fileSet := token.NewFileSet()
-	for fName := range fMap {
-		info, err := parser.ParseFile(fileSet, fName, nil, 0)
+	excludedGen := map[string][]GoInfo{}
+
+	for _, fName := range slices.Sorted(maps.Keys(fMap)) {
+		content, err := os.ReadFile(fName) //nolint:gosec
+		if err != nil {
+			mi.addScanError(errMap, err)
+			continue
+		}
+
+		included, err := opts.fileIsIncluded(fName, content)
 		if err != nil {
-			fmt.Println("\t", err)
+			mi.addScanError(errMap, err)
+			continue
+		}
+
+		if !included {
+			mi.ExcludedFiles = append(mi.ExcludedFiles,
+				strings.TrimPrefix(fName, dirName+string(filepath.Separator)))
+
+			continue
+		}
+
+		info, err := parser.ParseFile(fileSet, fName, content,
+			parser.ParseComments)
+		if err != nil {
+			mi.addScanError(errMap, err)
 			continue
 		}
 
@@ -199,20 +388,35 @@ func (mi *modInfo) getPackageInfo(dirName string) {
 		pName := info.Name.Name
 		basePName := strings.TrimSuffix(pName, "_test")
 
+		gi := getGoInfo(fileSet, info)
+
+		if gi.IsGenerated {
+			mi.GeneratedLoC += gi.LineCount
+
+			if !opts.includeGenerated {
+				excludedGen[importName] = append(excludedGen[importName], gi)
+				continue
+			}
+		}
+
 		pkg, ok := mi.Packages[importName]
 		if !ok {
 			pkg = &PkgInfo{
+				Mod:        mi,
 				Name:       basePName,
 				ImportName: importName,
 			}
 			mi.Packages[importName] = pkg
 		}
 
-		gi := getGoInfo(fileSet, info)
+		if gi.IsGenerated {
+			pkg.addGeneratedFile(gi)
+		}
 
 		if strings.HasSuffix(fName, "_test.go") {
 			pkg.TestFiles = append(pkg.TestFiles, gi)
 			pkg.TestFilesLoC += gi.LineCount
+			mi.TestLinesOfCode += gi.LineCount
 
 			if pName == basePName {
 				pkg.HasTestsInt = true
@@ -222,7 +426,24 @@ func (mi *modInfo) getPackageInfo(dirName string) {
 		} else {
 			pkg.Files = append(pkg.Files, gi)
 			pkg.FilesLoC += gi.LineCount
+			pkg.API.add(gi.API)
+			pkg.FilesLines.add(gi.Lines)
 			mi.LinesOfCode += gi.LineCount
+			mi.Lines.add(gi.Lines)
+
+			if pkg.isPublic() {
+				mi.API.add(gi.API)
+			}
+		}
+	}
+
+	// The excluded generated files are only recorded against packages
+	// having other files; a package of only generated files is not shown.
+	for importName, gis := range excludedGen {
+		if pkg, ok := mi.Packages[importName]; ok {
+			for _, gi := range gis {
+				pkg.addGeneratedFile(gi)
+			}
 		}
 	}
 }
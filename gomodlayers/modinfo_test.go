@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestAddScanError(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		err          error
+		expErrCount  int
+		expFirstText string
+	}{
+		{
+			ID:           testhelper.MkID("simple error"),
+			err:          errors.New("simple"),
+			expErrCount:  1,
+			expFirstText: "simple",
+		},
+		{
+			ID: testhelper.MkID("parser error list"),
+			err: scanner.ErrorList{
+				&scanner.Error{
+					Pos: token.Position{Filename: "a.go", Line: 3, Column: 1},
+					Msg: "expected 'package'",
+				},
+				&scanner.Error{
+					Pos: token.Position{Filename: "a.go", Line: 7, Column: 5},
+					Msg: "expected ';'",
+				},
+			},
+			expErrCount:  2,
+			expFirstText: "a.go:3:1: expected 'package'",
+		},
+	}
+
+	for _, tc := range testCases {
+		mi := newModInfo("example.com/mod")
+		errMap := errutil.NewErrMap()
+
+		mi.addScanError(errMap, tc.err)
+
+		testhelper.DiffInt(t, tc.IDStr(), "module scan errors",
+			len(mi.ScanErrors), tc.expErrCount)
+
+		errCount, _ := errMap.CountErrors()
+		testhelper.DiffInt(t, tc.IDStr(), "errMap errors",
+			errCount, tc.expErrCount)
+
+		if len(mi.ScanErrors) > 0 {
+			testhelper.DiffString(t, tc.IDStr(), "first error",
+				mi.ScanErrors[0].Error(), tc.expFirstText)
+		}
+	}
+}
+
+func TestGetPackageInfoGenerated(t *testing.T) {
+	const genHdr = "// Code generated by test. DO NOT EDIT.\n\n"
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.go"), "package a\n")
+	writeTestFile(t, filepath.Join(dir, "gen", "g_gen.go"),
+		genHdr+"package gen\n")
+	writeTestFile(t, filepath.Join(dir, "mixed", "m.go"), "package mixed\n")
+	writeTestFile(t, filepath.Join(dir, "mixed", "z_gen.go"),
+		genHdr+"package mixed\n")
+
+	testCases := []struct {
+		testhelper.ID
+		includeGenerated bool
+		expPkgs          []string
+		expMixedGenLoC   int
+	}{
+		{
+			ID: testhelper.MkID("generated files excluded"),
+			expPkgs: []string{
+				"example.com/A",
+				"example.com/A/mixed",
+			},
+			expMixedGenLoC: 3,
+		},
+		{
+			ID:               testhelper.MkID("generated files included"),
+			includeGenerated: true,
+			expPkgs: []string{
+				"example.com/A",
+				"example.com/A/gen",
+				"example.com/A/mixed",
+			},
+			expMixedGenLoC: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		mi := newModInfo("example.com/A")
+		errMap := errutil.NewErrMap()
+
+		mi.getPackageInfo(dir,
+			pkgScanOpts{
+				includeGenerated: tc.includeGenerated,
+				allPlatforms:     true,
+			},
+			errMap)
+
+		if errMap.HasErrors() {
+			t.Fatal(tc.IDStr(), ": unexpected errors scanning the packages")
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "packages",
+			slices.Sorted(maps.Keys(mi.Packages)), tc.expPkgs)
+		testhelper.DiffInt(t, tc.IDStr(), "module generated LoC",
+			mi.GeneratedLoC, 2*tc.expMixedGenLoC)
+
+		if pkg, ok := mi.Packages["example.com/A/mixed"]; ok {
+			testhelper.DiffInt(t, tc.IDStr(), "mixed package generated LoC",
+				pkg.GeneratedLoC, tc.expMixedGenLoC)
+		}
+	}
+}
+
+func TestGetPackageInfoTests(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.go"),
+		"package a\n\nfunc F() {}\n")
+	writeTestFile(t, filepath.Join(dir, "a_test.go"),
+		"package a\n\nimport \"testing\"\n\nfunc TestF(t *testing.T) {}\n")
+	writeTestFile(t, filepath.Join(dir, "api_test.go"),
+		"package a_test\n")
+	writeTestFile(t, filepath.Join(dir, "sub", "s.go"),
+		"package sub\n\nvar V int\n\nfunc G() {}\n")
+
+	mi := newModInfo("example.com/A")
+	errMap := errutil.NewErrMap()
+
+	mi.getPackageInfo(dir, pkgScanOpts{allPlatforms: true}, errMap)
+
+	if errMap.HasErrors() {
+		t.Fatal("unexpected errors scanning the packages")
+	}
+
+	const id = "packages with and without tests"
+
+	testhelper.DiffInt(t, id, "lines of code", mi.LinesOfCode, 3+5)
+	testhelper.DiffInt(t, id, "test lines of code", mi.TestLinesOfCode, 5+1)
+	testhelper.DiffInt(t, id, "untested packages", mi.untestedPkgCount(), 1)
+	testhelper.DiffFloat(t, id, "test ratio", mi.testRatio(), 0.75, 0.0)
+
+	pkg := mi.Packages["example.com/A"]
+	if pkg == nil {
+		t.Fatal(id, ": the package example.com/A was not found")
+	}
+
+	testhelper.DiffInt(t, id, "package test lines of code",
+		pkg.TestFilesLoC, 6)
+	testhelper.DiffBool(t, id, "package has internal tests",
+		pkg.HasTestsInt, true)
+	testhelper.DiffBool(t, id, "package has external tests",
+		pkg.HasTestsAPI, true)
+}
+
+func TestTestMetrics(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		loc         int
+		testLoC     int
+		pkgs        []PkgInfo
+		expRatio    float64
+		expUntested int
+	}{
+		{
+			ID:       testhelper.MkID("no code"),
+			testLoC:  10,
+			expRatio: 0,
+		},
+		{
+			ID:          testhelper.MkID("no tests"),
+			loc:         10,
+			pkgs:        []PkgInfo{{ImportName: "a"}, {ImportName: "b"}},
+			expRatio:    0,
+			expUntested: 2,
+		},
+		{
+			ID:      testhelper.MkID("some tests"),
+			loc:     20,
+			testLoC: 30,
+			pkgs: []PkgInfo{
+				{ImportName: "a", HasTestsInt: true},
+				{ImportName: "b", HasTestsAPI: true},
+				{ImportName: "c"},
+			},
+			expRatio:    1.5,
+			expUntested: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		mi := newModInfo("example.com/A")
+		mi.LinesOfCode = tc.loc
+		mi.TestLinesOfCode = tc.testLoC
+
+		for _, pi := range tc.pkgs {
+			mi.Packages[pi.ImportName] = &pi
+		}
+
+		testhelper.DiffFloat(t, tc.IDStr(), "test ratio",
+			mi.testRatio(), tc.expRatio, 0.0)
+		testhelper.DiffInt(t, tc.IDStr(), "untested packages",
+			mi.untestedPkgCount(), tc.expUntested)
+	}
+}
+
+func TestGetPackageInfoAPI(t *testing.T) {
+	const api = "package %s\n\nfunc F() {}\n\ntype T struct{}\n"
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.go"), fmt.Sprintf(api, "a"))
+	writeTestFile(t, filepath.Join(dir, "cmd", "tool", "main.go"),
+		fmt.Sprintf(api, "main"))
+	writeTestFile(t, filepath.Join(dir, "internal", "x", "x.go"),
+		fmt.Sprintf(api, "x"))
+
+	mi := newModInfo("example.com/A")
+	errMap := errutil.NewErrMap()
+
+	mi.getPackageInfo(dir, pkgScanOpts{allPlatforms: true}, errMap)
+
+	if errMap.HasErrors() {
+		t.Fatal("unexpected errors scanning the packages")
+	}
+
+	const id = "commands and internal packages"
+
+	testhelper.DiffInt(t, id, "module funcs", mi.API.Funcs, 1)
+	testhelper.DiffInt(t, id, "module types", mi.API.Types, 1)
+
+	for _, name := range []string{
+		"example.com/A/cmd/tool",
+		"example.com/A/internal/x",
+	} {
+		pkg, ok := mi.Packages[name]
+		if !ok {
+			t.Errorf("%s: the package %s was not found", id, name)
+			continue
+		}
+
+		testhelper.DiffInt(t, id, name+" funcs", pkg.API.Funcs, 1)
+	}
+}
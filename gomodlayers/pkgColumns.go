@@ -0,0 +1,413 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nickwells/col.mod/v6/col"
+	"github.com/nickwells/col.mod/v6/colfmt"
+	"github.com/nickwells/col.mod/v6/rptmaker"
+)
+
+// these constants name the available package report columns
+const (
+	PkgColModule     = rptmaker.ColID("module")
+	PkgColImportPath = rptmaker.ColID("import-path")
+	PkgColLines      = rptmaker.ColID("lines-of-code")
+	PkgColTestLines  = rptmaker.ColID("test-lines-of-code")
+	PkgColTests      = rptmaker.ColID("tests")
+	PkgColName       = rptmaker.ColID("package-name")
+	PkgColFiles      = rptmaker.ColID("files")
+	PkgColTestFiles  = rptmaker.ColID("test-files")
+	PkgColIntTests   = rptmaker.ColID("internal-tests")
+	PkgColExtTests   = rptmaker.ColID("external-tests")
+	PkgColIsCmd      = rptmaker.ColID("command")
+	PkgColGenLines   = rptmaker.ColID("generated-lines-of-code")
+
+	PkgAliasLoC     = rptmaker.ColID("loc")
+	PkgAliasTestLoC = rptmaker.ColID("test-loc")
+	PkgAliasPath    = rptmaker.ColID("path")
+	PkgAliasAll     = rptmaker.ColID("all")
+	PkgAliasAPI     = rptmaker.ColID("api")
+
+	pkgTestStatusWidth = len("internal+external")
+)
+
+// pkgTestCols gives the columns shown in the package tests report
+var pkgTestCols = []rptmaker.ColID{
+	PkgColModule,
+	PkgColImportPath,
+	PkgColLines,
+	PkgColTestLines,
+	PkgColTests,
+}
+
+// addPkgColModule adds the module column to the supplied cols parameter.
+func addPkgColModule(p *prog, cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColModule,
+		rptmaker.NewColInfo(
+			"this is the name of the module containing the package.",
+			[]string{"Module name"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: prog.maxNameLen}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any {
+				return strings.TrimPrefix(pi.Mod.Name, p.stripPrefix)
+			},
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return strings.Compare(a.Mod.Name, b.Mod.Name)
+			}))
+}
+
+// addPkgColImportPath adds the importPath column to the supplied cols
+// parameter.
+func addPkgColImportPath(p *prog, cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColImportPath,
+		rptmaker.NewColInfo(
+			"this is the path by which the package is imported.",
+			[]string{"Import path"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: prog.maxPkgNameLen},
+					headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any {
+				return strings.TrimPrefix(pi.ImportName, p.stripPrefix)
+			},
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return strings.Compare(a.ImportName, b.ImportName)
+			}))
+}
+
+// addPkgColLines adds the lines column to the supplied cols parameter.
+func addPkgColLines(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColLines,
+		rptmaker.NewColInfo(
+			"this gives the number of lines of non-test code"+
+				" in the package.",
+			[]string{"LoC"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.FilesLoC },
+			// cmpVals
+			func(a, b *PkgInfo) int { return a.FilesLoC - b.FilesLoC },
+		))
+}
+
+// addPkgColTestLines adds the testLines column to the supplied cols
+// parameter.
+func addPkgColTestLines(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColTestLines,
+		rptmaker.NewColInfo(
+			"this gives the number of lines of test code"+
+				" in the package.",
+			[]string{"Test", "LoC"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.TestFilesLoC },
+			// cmpVals
+			func(a, b *PkgInfo) int { return a.TestFilesLoC - b.TestFilesLoC },
+		))
+}
+
+// addPkgColTests adds the tests column to the supplied cols parameter.
+func addPkgColTests(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColTests,
+		rptmaker.NewColInfo(
+			"this shows what tests the package has."+
+				" Internal tests are in the same package as the code"+
+				" being tested and external tests are in a"+
+				" separate '_test' package and so can only"+
+				" test the exported API."+
+				" A package with no tests is shown as 'none'.",
+			[]string{"Tests"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: pkgTestStatusWidth},
+					headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.testStatus() },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return strings.Compare(a.testStatus(), b.testStatus())
+			}))
+}
+
+// addPkgColGenLines adds the genLines column to the supplied cols
+// parameter.
+func addPkgColGenLines(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColGenLines,
+		rptmaker.NewColInfo(
+			"this gives the number of lines of generated code"+
+				" in the package, including any generated test code.",
+			[]string{"Generated", "LoC"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.GeneratedLoC },
+			// cmpVals
+			func(a, b *PkgInfo) int { return a.GeneratedLoC - b.GeneratedLoC },
+		))
+}
+
+// cmpBool compares two bool values, false is taken to be less than true
+func cmpBool(a, b bool) int {
+	if a == b {
+		return 0
+	}
+
+	if a {
+		return 1
+	}
+
+	return -1
+}
+
+// addPkgColName adds the name column to the supplied cols parameter.
+func addPkgColName(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColName,
+		rptmaker.NewColInfo(
+			"this is the name of the package as given in"+
+				" the package clause of its Go files.",
+			[]string{"Package", "Name"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.String{W: prog.maxPkgShortNameLen},
+					headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.Name },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return strings.Compare(a.Name, b.Name)
+			}))
+}
+
+// addPkgColFiles adds the files column to the supplied cols parameter.
+func addPkgColFiles(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColFiles,
+		rptmaker.NewColInfo(
+			"this gives the number of non-test Go files in the package.",
+			[]string{"File", "Count"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return len(pi.Files) },
+			// cmpVals
+			func(a, b *PkgInfo) int { return len(a.Files) - len(b.Files) },
+		))
+}
+
+// addPkgColTestFiles adds the testFiles column to the supplied cols
+// parameter.
+func addPkgColTestFiles(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColTestFiles,
+		rptmaker.NewColInfo(
+			"this gives the number of Go test files in the package.",
+			[]string{"Test File", "Count"},
+			// mkCol
+			func(prog *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Int{W: prog.reportDigits}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return len(pi.TestFiles) },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return len(a.TestFiles) - len(b.TestFiles)
+			},
+		))
+}
+
+// addPkgColIntTests adds the intTests column to the supplied cols
+// parameter.
+func addPkgColIntTests(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColIntTests,
+		rptmaker.NewColInfo(
+			"this shows whether the package has internal tests."+
+				" These are tests in the same package as the code"+
+				" being tested.",
+			[]string{"Internal", "Tests"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Bool{}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.HasTestsInt },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return cmpBool(a.HasTestsInt, b.HasTestsInt)
+			},
+		))
+}
+
+// addPkgColExtTests adds the extTests column to the supplied cols
+// parameter.
+func addPkgColExtTests(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColExtTests,
+		rptmaker.NewColInfo(
+			"this shows whether the package has external tests."+
+				" These are tests in a separate '_test' package"+
+				" and so can only test the exported API.",
+			[]string{"External", "Tests"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Bool{}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.HasTestsAPI },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return cmpBool(a.HasTestsAPI, b.HasTestsAPI)
+			},
+		))
+}
+
+// addPkgColIsCmd adds the isCmd column to the supplied cols parameter.
+func addPkgColIsCmd(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	return cols.Add(PkgColIsCmd,
+		rptmaker.NewColInfo(
+			"this shows whether the package is a command"+
+				" (it has the package name 'main')"+
+				" rather than a library.",
+			[]string{"Is", "Command"},
+			// mkCol
+			func(_ *prog, headings []string) *col.Col {
+				return col.New(&colfmt.Bool{}, headings...)
+			},
+			// colVal
+			func(pi *PkgInfo) any { return pi.isCommand() },
+			// cmpVals
+			func(a, b *PkgInfo) int {
+				return cmpBool(a.isCommand(), b.isCommand())
+			},
+		))
+}
+
+// addPkgColsAPI adds the exported API columns to the supplied cols
+// parameter.
+func addPkgColsAPI(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	allErrs := []error{}
+
+	for _, acd := range apiColDescs {
+		allErrs = append(allErrs, cols.Add(acd.id,
+			rptmaker.NewColInfo(
+				acd.desc+" in the non-test code of the package."+acd.note,
+				acd.headings,
+				// mkCol
+				func(prog *prog, headings []string) *col.Col {
+					return col.New(&colfmt.Int{W: prog.reportDigits},
+						headings...)
+				},
+				// colVal
+				func(pi *PkgInfo) any { return acd.count(pi.API) },
+				// cmpVals
+				func(a, b *PkgInfo) int {
+					return acd.count(a.API) - acd.count(b.API)
+				},
+			)))
+	}
+
+	return errors.Join(allErrs...)
+}
+
+// addPkgColsLines adds the line count columns to the supplied cols
+// parameter.
+func addPkgColsLines(cols *rptmaker.Cols[*prog, *PkgInfo]) error {
+	allErrs := []error{}
+
+	for _, lcd := range lineColDescs {
+		allErrs = append(allErrs, cols.Add(lcd.id,
+			rptmaker.NewColInfo(
+				lcd.desc+" in the non-test code of the package.",
+				lcd.headings,
+				// mkCol
+				func(prog *prog, headings []string) *col.Col {
+					return col.New(&colfmt.Int{W: prog.reportDigits},
+						headings...)
+				},
+				// colVal
+				func(pi *PkgInfo) any { return lcd.count(pi.FilesLines) },
+				// cmpVals
+				func(a, b *PkgInfo) int {
+					return lcd.count(a.FilesLines) - lcd.count(b.FilesLines)
+				},
+			)))
+	}
+
+	return errors.Join(allErrs...)
+}
+
+// populatePkgCols populates and returns the package report columns
+func (p *prog) populatePkgCols() *rptmaker.Cols[*prog, *PkgInfo] {
+	allErrs := []error{}
+	cols := rptmaker.NewCols[*prog, *PkgInfo]()
+
+	allErrs = append(allErrs, addPkgColModule(p, cols))
+	allErrs = append(allErrs, addPkgColImportPath(p, cols))
+	allErrs = append(allErrs, addPkgColLines(cols))
+	allErrs = append(allErrs, addPkgColTestLines(cols))
+	allErrs = append(allErrs, addPkgColTests(cols))
+	allErrs = append(allErrs, addPkgColName(cols))
+	allErrs = append(allErrs, addPkgColFiles(cols))
+	allErrs = append(allErrs, addPkgColTestFiles(cols))
+	allErrs = append(allErrs, addPkgColIntTests(cols))
+	allErrs = append(allErrs, addPkgColExtTests(cols))
+	allErrs = append(allErrs, addPkgColIsCmd(cols))
+	allErrs = append(allErrs, addPkgColsAPI(cols))
+	allErrs = append(allErrs, addPkgColsLines(cols))
+	allErrs = append(allErrs, addPkgColGenLines(cols))
+
+	allErrs = append(allErrs, cols.AddAlias(PkgAliasLoC, PkgColLines))
+	allErrs = append(allErrs, cols.AddAlias(PkgAliasTestLoC, PkgColTestLines))
+	allErrs = append(allErrs, cols.AddAlias(PkgAliasPath, PkgColImportPath))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(PkgAliasAll,
+		PkgColModule,
+		PkgColImportPath,
+		PkgColName,
+		PkgColFiles,
+		PkgColLines,
+		ColCodeLines,
+		ColCommentLines,
+		ColBlankLines,
+		PkgColGenLines,
+		PkgColTestFiles,
+		PkgColTestLines,
+		PkgColIntTests,
+		PkgColExtTests,
+		PkgColIsCmd,
+		ColAPITotal,
+	))
+
+	allErrs = append(allErrs, cols.AddReportableAlias(PkgAliasAPI,
+		PkgColImportPath,
+		ColAPITypes,
+		ColAPIFuncs,
+		ColAPIMethods,
+		ColAPIConsts,
+		ColAPIVars,
+		ColAPITotal,
+	))
+
+	if errs := errors.Join(allErrs...); errs != nil {
+		panic(errs)
+	}
+
+	return cols
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// addTestPkg adds a package with the given details to the module
+func addTestPkg(mi *modInfo, pi *PkgInfo) {
+	pi.Mod = mi
+	mi.Packages[pi.ImportName] = pi
+}
+
+// mkTestPkgProg returns a prog with the modules A and B having packages as
+// follows:
+//
+//	A             - 10 LoC, 5 test LoC, internal tests, 2 funcs
+//	A/cmd/longcmd - a command, 20 LoC, 7 generated LoC, no tests
+//	B/v2          - 3 LoC, 30 test LoC, external tests, 1 type
+func mkTestPkgProg(t *testing.T) *prog {
+	t.Helper()
+
+	prog := newProg()
+	prog.mm = mkTestModMap(t,
+		"module example.com/A\n",
+		"module example.com/B/v2\n")
+	prog.showIntro = false
+
+	a := prog.mm["example.com/A"]
+	addTestPkg(a, &PkgInfo{
+		Name:         "a",
+		ImportName:   "example.com/A",
+		Files:        []GoInfo{{}},
+		FilesLoC:     10,
+		TestFiles:    []GoInfo{{}},
+		TestFilesLoC: 5,
+		HasTestsInt:  true,
+		API:          APICounts{Funcs: 2},
+	})
+	addTestPkg(a, &PkgInfo{
+		Name:           "main",
+		ImportName:     "example.com/A/cmd/longcmd",
+		Files:          []GoInfo{{}, {}},
+		FilesLoC:       20,
+		GeneratedFiles: []GoInfo{{}},
+		GeneratedLoC:   7,
+	})
+
+	b := prog.mm["example.com/B/v2"]
+	addTestPkg(b, &PkgInfo{
+		Name:         "b",
+		ImportName:   "example.com/B/v2",
+		Files:        []GoInfo{{}},
+		FilesLoC:     3,
+		TestFiles:    []GoInfo{{}, {}},
+		TestFilesLoC: 30,
+		HasTestsAPI:  true,
+		API:          APICounts{Types: 1},
+	})
+
+	prog.populateModInfo()
+
+	return prog
+}
+
+func TestSetMaxPkgNameLens(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		stripPrefix    string
+		expMaxNameLen  int
+		expMaxShortLen int
+	}{
+		{
+			ID:             testhelper.MkID("no prefix stripped"),
+			expMaxNameLen:  len("example.com/A/cmd/longcmd"),
+			expMaxShortLen: len("main"),
+		},
+		{
+			ID:             testhelper.MkID("prefix stripped"),
+			stripPrefix:    "example.com/",
+			expMaxNameLen:  len("A/cmd/longcmd"),
+			expMaxShortLen: len("main"),
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := mkTestPkgProg(t)
+		prog.stripPrefix = tc.stripPrefix
+		prog.setMaxPkgNameLens()
+
+		testhelper.DiffInt(t, tc.IDStr(), "max import name length",
+			prog.maxPkgNameLen, tc.expMaxNameLen)
+		testhelper.DiffInt(t, tc.IDStr(), "max package name length",
+			prog.maxPkgShortNameLen, tc.expMaxShortLen)
+	}
+}
+
+func TestReportPackages(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		stripPrefix string
+		colIDs      []rptmaker.ColID
+		sortCols    []rptmaker.SortColumn
+		expOut      string
+	}{
+		{
+			ID:          testhelper.MkID("default columns"),
+			stripPrefix: "example.com/",
+			colIDs:      newProg().pkgColumnsToShow,
+			sortCols: []rptmaker.SortColumn{
+				{ID: PkgColModule},
+				{ID: PkgColImportPath},
+			},
+			expOut: "                          Package " +
+				" File        Test                  \n" +
+				"Module name Import path   Name    " +
+				"Count   LoC   LoC Tests            \n" +
+				"=========== ===========   ====    " +
+				"=====   ===   === =====            \n" +
+				"A           A             a       " +
+				"    1    10     5 internal         \n" +
+				"A           A/cmd/longcmd main    " +
+				"    2    20     0 none             \n" +
+				"B/v2        B/v2          b       " +
+				"    1     3    30 external         \n",
+		},
+		{
+			ID: testhelper.MkID("new columns, no prefix stripped"),
+			colIDs: []rptmaker.ColID{
+				PkgColImportPath,
+				PkgColTestFiles,
+				PkgColIntTests,
+				PkgColExtTests,
+				PkgColIsCmd,
+				PkgColGenLines,
+			},
+			sortCols: []rptmaker.SortColumn{{ID: PkgColImportPath}},
+			expOut: "                          Test File" +
+				" Internal External Is      Generated\n" +
+				"Import path                   Count" +
+				" Tests    Tests    Command       LoC\n" +
+				"===========                   =====" +
+				" =====    =====    =======       ===\n" +
+				"example.com/A                     1" +
+				" true     false    false           0\n" +
+				"example.com/A/cmd/longcmd         0" +
+				" false    false    true            7\n" +
+				"example.com/B/v2                  2" +
+				" false    true     false           0\n",
+		},
+		{
+			ID:          testhelper.MkID("aliases, sorted by LoC descending"),
+			stripPrefix: "example.com/",
+			colIDs: []rptmaker.ColID{
+				PkgAliasPath,
+				PkgAliasLoC,
+				PkgAliasTestLoC,
+			},
+			sortCols: []rptmaker.SortColumn{
+				{ID: PkgColLines, Backwards: true},
+			},
+			expOut: "                     Test\n" +
+				"Import path     LoC   LoC\n" +
+				"===========     ===   ===\n" +
+				"A/cmd/longcmd    20     0\n" +
+				"A                10     5\n" +
+				"B/v2              3    30\n",
+		},
+		{
+			ID:          testhelper.MkID("api alias"),
+			stripPrefix: "example.com/",
+			colIDs:      []rptmaker.ColID{PkgAliasAPI},
+			sortCols:    []rptmaker.SortColumn{{ID: PkgColImportPath}},
+			expOut: "              ------------Exported------------   API\n" +
+				"Import path   Types Funcs Methods Consts  Vars  Size\n" +
+				"===========   ===== ===== ======= ======  ====  ====\n" +
+				"A                 0     2       0      0     0     2\n" +
+				"A/cmd/longcmd     0     0       0      0     0     0\n" +
+				"B/v2              1     0       0      0     0     1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := mkTestPkgProg(t)
+		prog.stripPrefix = tc.stripPrefix
+
+		colIDs := []rptmaker.ColID{}
+		aliases := prog.populatePkgCols().ReportableAliases()
+
+		for _, cid := range tc.colIDs {
+			if alias, ok := aliases[cid]; ok {
+				colIDs = append(colIDs, alias...)
+			} else {
+				colIDs = append(colIDs, cid)
+			}
+		}
+
+		var buf bytes.Buffer
+
+		prog.reportPackages(&buf, "", colIDs, tc.sortCols)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
+
+func TestPkgColAliases(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		alias       rptmaker.ColID
+		expRptCols  []rptmaker.ColID
+		expSortCols []rptmaker.ColID
+	}{
+		{
+			ID:          testhelper.MkID("loc"),
+			alias:       PkgAliasLoC,
+			expRptCols:  []rptmaker.ColID{PkgColLines},
+			expSortCols: []rptmaker.ColID{PkgColLines},
+		},
+		{
+			ID:          testhelper.MkID("test-loc"),
+			alias:       PkgAliasTestLoC,
+			expRptCols:  []rptmaker.ColID{PkgColTestLines},
+			expSortCols: []rptmaker.ColID{PkgColTestLines},
+		},
+		{
+			ID:          testhelper.MkID("path"),
+			alias:       PkgAliasPath,
+			expRptCols:  []rptmaker.ColID{PkgColImportPath},
+			expSortCols: []rptmaker.ColID{PkgColImportPath},
+		},
+		{
+			ID:    testhelper.MkID("api"),
+			alias: PkgAliasAPI,
+			expRptCols: []rptmaker.ColID{
+				PkgColImportPath,
+				ColAPITypes,
+				ColAPIFuncs,
+				ColAPIMethods,
+				ColAPIConsts,
+				ColAPIVars,
+				ColAPITotal,
+			},
+		},
+	}
+
+	cols := newProg().populatePkgCols()
+	rptAliases := cols.ReportableAliases()
+	sortAliases := cols.SortableAliases()
+
+	for _, tc := range testCases {
+		testhelper.DiffSlice(t, tc.IDStr(), "reportable columns",
+			rptAliases[tc.alias], tc.expRptCols)
+		testhelper.DiffSlice(t, tc.IDStr(), "sortable columns",
+			sortAliases[tc.alias], tc.expSortCols)
+	}
+}
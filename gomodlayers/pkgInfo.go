@@ -3,17 +3,66 @@ package main
 import (
 	"go/ast"
 	"go/token"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
 )
 
+// APICounts records the number of exported identifiers of each kind
+type APICounts struct {
+	Types      int
+	Interfaces int
+	Funcs      int
+	Methods    int
+	Consts     int
+	Vars       int
+}
+
+// add adds the counts from the other APICounts to this one
+func (ac *APICounts) add(other APICounts) {
+	ac.Types += other.Types
+	ac.Interfaces += other.Interfaces
+	ac.Funcs += other.Funcs
+	ac.Methods += other.Methods
+	ac.Consts += other.Consts
+	ac.Vars += other.Vars
+}
+
+// total returns the total number of exported identifiers. Note that the
+// interfaces are already counted in the types.
+func (ac APICounts) total() int {
+	return ac.Types + ac.Funcs + ac.Methods + ac.Consts + ac.Vars
+}
+
+// LineCounts records the number of lines of each kind in some Go code
+type LineCounts struct {
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// add adds the counts from the other LineCounts to this one
+func (lc *LineCounts) add(other LineCounts) {
+	lc.Code += other.Code
+	lc.Comment += other.Comment
+	lc.Blank += other.Blank
+}
+
 // GoInfo records Go information about a file
 type GoInfo struct {
-	FileName  string
-	LineCount int
-	Info      *ast.File
+	FileName    string
+	LineCount   int
+	Lines       LineCounts
+	API         APICounts
+	IsGenerated bool
+	Info        *ast.File
 }
 
 // PkgInfo records aggregate package information
 type PkgInfo struct {
+	Mod          *modInfo
 	Name         string
 	ImportName   string
 	Files        []GoInfo
@@ -22,16 +71,228 @@ type PkgInfo struct {
 	TestFilesLoC int
 	HasTestsInt  bool
 	HasTestsAPI  bool
+	API          APICounts
+	FilesLines   LineCounts
+
+	GeneratedFiles []GoInfo
+	GeneratedLoC   int
+}
+
+// isCommand returns true if the package is a command (has package name
+// "main")
+func (pi *PkgInfo) isCommand() bool {
+	return pi.Name == "main"
+}
+
+// commandName returns the name of the command that the package would be
+// installed as. This is the last part of the import path unless that is a
+// major version suffix in which case it is the part before.
+func (pi *PkgInfo) commandName() string {
+	importName := pi.ImportName
+	if prefix, pathMajor, ok := module.SplitPathVersion(importName); ok &&
+		pathMajor != "" {
+		importName = prefix
+	}
+
+	return path.Base(importName)
+}
+
+// isInternal returns true if the package is an internal package, one which
+// can only be imported by the packages rooted at the parent of the internal
+// directory
+func (pi *PkgInfo) isInternal() bool {
+	return slices.Contains(strings.Split(pi.ImportName, "/"), "internal")
+}
+
+// isPublic returns true if the package can be imported by other modules.
+// The exported names of commands and internal packages are not part of the
+// API of the module.
+func (pi *PkgInfo) isPublic() bool {
+	return !pi.isCommand() && !pi.isInternal()
+}
+
+// hasTests returns true if the package has any tests
+func (pi *PkgInfo) hasTests() bool {
+	return pi.HasTestsInt || pi.HasTestsAPI
+}
+
+// testStatus returns a string describing the tests that the package has
+func (pi *PkgInfo) testStatus() string {
+	switch {
+	case pi.HasTestsInt && pi.HasTestsAPI:
+		return "internal+external"
+	case pi.HasTestsInt:
+		return "internal"
+	case pi.HasTestsAPI:
+		return "external"
+	}
+
+	return "none"
+}
+
+// addGeneratedFile records the generated file against the package
+func (pi *PkgInfo) addGeneratedFile(gi GoInfo) {
+	pi.GeneratedFiles = append(pi.GeneratedFiles, gi)
+	pi.GeneratedLoC += gi.LineCount
 }
 
 // getGoInfo finds Go information from the Go File
 func getGoInfo(fileSet *token.FileSet, info *ast.File) GoInfo {
 	file := fileSet.File(info.Pos())
 	gi := GoInfo{
-		FileName:  file.Name(),
-		LineCount: file.LineCount(),
-		Info:      info,
+		FileName:    file.Name(),
+		LineCount:   file.LineCount(),
+		Lines:       countLines(file, info),
+		API:         countAPI(info),
+		IsGenerated: ast.IsGenerated(info),
+		Info:        info,
 	}
 
 	return gi
 }
+
+// countLines counts the lines of code, of comments and the blank lines in
+// the file. A line is taken to be a line of code if any part of the code
+// starts or ends on it. A line is taken to be a comment line if it is not a
+// line of code and any part of a comment is on it. Any other line is taken
+// to be blank. Note that the AST must have been generated with the comments
+// retained for the comment lines to be counted.
+func countLines(file *token.File, info *ast.File) LineCounts {
+	lineCount := file.LineCount()
+	isCode := make([]bool, lineCount+1)
+	isComment := make([]bool, lineCount+1)
+
+	markLines := func(lines []bool, from, to token.Pos) {
+		for l := file.Line(from); l <= file.Line(to); l++ {
+			lines[l] = true
+		}
+	}
+
+	ast.Inspect(info, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+
+		if !n.Pos().IsValid() || !n.End().IsValid() {
+			return true
+		}
+
+		if _, ok := n.(*ast.BasicLit); ok {
+			// a raw string literal may span several lines
+			markLines(isCode, n.Pos(), n.End()-1)
+			return false
+		}
+
+		isCode[file.Line(n.Pos())] = true
+		isCode[file.Line(n.End()-1)] = true
+
+		return true
+	})
+
+	for _, cg := range info.Comments {
+		for _, c := range cg.List {
+			markLines(isComment, c.Pos(), c.End()-1)
+		}
+	}
+
+	var lc LineCounts
+
+	for l := 1; l <= lineCount; l++ {
+		switch {
+		case isCode[l]:
+			lc.Code++
+		case isComment[l]:
+			lc.Comment++
+		default:
+			lc.Blank++
+		}
+	}
+
+	return lc
+}
+
+// countAPI counts the exported identifiers declared in the file. Methods
+// are only counted if the receiver type is also exported.
+func countAPI(info *ast.File) APICounts {
+	var ac APICounts
+
+	for _, decl := range info.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			ac.countFunc(d)
+		case *ast.GenDecl:
+			ac.countGenDecl(d)
+		}
+	}
+
+	return ac
+}
+
+// countFunc counts the function declaration if it is exported
+func (ac *APICounts) countFunc(fd *ast.FuncDecl) {
+	if !fd.Name.IsExported() {
+		return
+	}
+
+	if fd.Recv == nil {
+		ac.Funcs++
+		return
+	}
+
+	if len(fd.Recv.List) > 0 && recvTypeIsExported(fd.Recv.List[0].Type) {
+		ac.Methods++
+	}
+}
+
+// recvTypeIsExported returns true if the base type of the method receiver
+// is exported
+func recvTypeIsExported(expr ast.Expr) bool {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+// countGenDecl counts the exported types, constants and variables in the
+// declaration
+func (ac *APICounts) countGenDecl(gd *ast.GenDecl) {
+	for _, spec := range gd.Specs {
+		switch s := spec.(type) {
+		case *ast.TypeSpec:
+			if !s.Name.IsExported() {
+				continue
+			}
+
+			ac.Types++
+
+			if _, ok := s.Type.(*ast.InterfaceType); ok {
+				ac.Interfaces++
+			}
+		case *ast.ValueSpec:
+			for _, name := range s.Names {
+				if !name.IsExported() {
+					continue
+				}
+
+				if gd.Tok == token.CONST {
+					ac.Consts++
+				} else {
+					ac.Vars++
+				}
+			}
+		}
+	}
+}
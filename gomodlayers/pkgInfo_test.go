@@ -0,0 +1,231 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// parseTestFile parses the source and returns the file set and the AST. Any
+// parse errors are reported as fatal errors.
+func parseTestFile(t *testing.T, src string) (*token.FileSet, *ast.File) {
+	t.Helper()
+
+	fileSet := token.NewFileSet()
+
+	info, err := parser.ParseFile(fileSet, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("cannot parse the test source: %s", err)
+	}
+
+	return fileSet, info
+}
+
+func TestCountAPI(t *testing.T) {
+	const src = `package p
+
+type (
+	Exported   struct{}
+	unexported struct{}
+	Iface      interface{ M() }
+	iface      interface{ m() }
+)
+
+type Other int
+
+type Gen[T any] struct{}
+
+func (Exported) M1() {}
+func (*Exported) M2() {}
+func (Exported) m3()      {}
+func (unexported) M4() {}
+func (g *Gen[T]) M5() {}
+func F() {}
+func f() {}
+
+const (
+	C1, c2 = 1, 2
+	C3     = 3
+)
+
+var V1, v2, V3 int
+`
+	_, info := parseTestFile(t, src)
+
+	ac := countAPI(info)
+
+	const id = "countAPI"
+
+	testhelper.DiffInt(t, id, "types", ac.Types, 4)
+	testhelper.DiffInt(t, id, "interfaces", ac.Interfaces, 1)
+	testhelper.DiffInt(t, id, "funcs", ac.Funcs, 1)
+	testhelper.DiffInt(t, id, "methods", ac.Methods, 3)
+	testhelper.DiffInt(t, id, "consts", ac.Consts, 2)
+	testhelper.DiffInt(t, id, "vars", ac.Vars, 2)
+	testhelper.DiffInt(t, id, "total", ac.total(), 12)
+}
+
+func TestTestStatus(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		pi        PkgInfo
+		expStatus string
+		expTested bool
+	}{
+		{
+			ID:        testhelper.MkID("no tests"),
+			expStatus: "none",
+		},
+		{
+			ID:        testhelper.MkID("internal"),
+			pi:        PkgInfo{HasTestsInt: true},
+			expStatus: "internal",
+			expTested: true,
+		},
+		{
+			ID:        testhelper.MkID("external"),
+			pi:        PkgInfo{HasTestsAPI: true},
+			expStatus: "external",
+			expTested: true,
+		},
+		{
+			ID:        testhelper.MkID("both"),
+			pi:        PkgInfo{HasTestsInt: true, HasTestsAPI: true},
+			expStatus: "internal+external",
+			expTested: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "status",
+			tc.pi.testStatus(), tc.expStatus)
+		testhelper.DiffBool(t, tc.IDStr(), "has tests",
+			tc.pi.hasTests(), tc.expTested)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		importName string
+		expName    string
+	}{
+		{
+			ID:         testhelper.MkID("simple"),
+			importName: "example.com/tools/cmd/mytool",
+			expName:    "mytool",
+		},
+		{
+			ID:         testhelper.MkID("module root"),
+			importName: "example.com/mytool",
+			expName:    "mytool",
+		},
+		{
+			ID:         testhelper.MkID("major version"),
+			importName: "example.com/mytool/v2",
+			expName:    "mytool",
+		},
+	}
+
+	for _, tc := range testCases {
+		pi := PkgInfo{Name: "main", ImportName: tc.importName}
+		testhelper.DiffString(t, tc.IDStr(), "command name",
+			pi.commandName(), tc.expName)
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	const src = `// Package p is a test package
+package p
+
+/*
+A block comment
+
+with a blank line
+*/
+
+// F does nothing
+func F() string { // trailing comment
+	s := ` + "`" + `a raw
+
+string` + "`" + `
+
+	return s
+}
+`
+	fileSet, info := parseTestFile(t, src)
+
+	gi := getGoInfo(fileSet, info)
+
+	const id = "countLines"
+
+	testhelper.DiffInt(t, id, "total lines", gi.LineCount, 17)
+	testhelper.DiffInt(t, id, "code lines", gi.Lines.Code, 7)
+	testhelper.DiffInt(t, id, "comment lines", gi.Lines.Comment, 7)
+	testhelper.DiffInt(t, id, "blank lines", gi.Lines.Blank, 3)
+}
+
+func TestIsGenerated(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		src    string
+		expGen bool
+	}{
+		{
+			ID:  testhelper.MkID("hand-written"),
+			src: "// Package p is hand-written\npackage p\n",
+		},
+		{
+			ID: testhelper.MkID("generated"),
+			src: "// Code generated by protoc-gen-go. DO NOT EDIT.\n\n" +
+				"package p\n",
+			expGen: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		fileSet, info := parseTestFile(t, tc.src)
+		gi := getGoInfo(fileSet, info)
+		testhelper.DiffBool(t, tc.IDStr(), "is generated",
+			gi.IsGenerated, tc.expGen)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		pi        PkgInfo
+		expPublic bool
+	}{
+		{
+			ID:        testhelper.MkID("library"),
+			pi:        PkgInfo{Name: "a", ImportName: "example.com/A/lib"},
+			expPublic: true,
+		},
+		{
+			ID: testhelper.MkID("command"),
+			pi: PkgInfo{Name: "main", ImportName: "example.com/A/cmd/tool"},
+		},
+		{
+			ID: testhelper.MkID("internal"),
+			pi: PkgInfo{Name: "internal", ImportName: "example.com/A/internal"},
+		},
+		{
+			ID: testhelper.MkID("below internal"),
+			pi: PkgInfo{Name: "x", ImportName: "example.com/A/internal/x"},
+		},
+		{
+			ID:        testhelper.MkID("internal as part of a name"),
+			pi:        PkgInfo{Name: "x", ImportName: "example.com/A/internals/x"},
+			expPublic: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "public",
+			tc.pi.isPublic(), tc.expPublic)
+	}
+}
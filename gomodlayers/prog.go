@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"go/build"
 	"io"
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/nickwells/col.mod/v6/col"
@@ -14,14 +17,38 @@ import (
 	"github.com/nickwells/twrap.mod/twrap"
 )
 
+const (
+	modReportIntro = "This gives information about a collection of modules" +
+		" and how they relate to one another." +
+		" The information in this report can be interpreted as follows."
+	pkgReportIntro = "This gives information about each of the" +
+		" packages in a collection of modules." +
+		" The information in this report can be interpreted as follows."
+	pkgTestsReportIntro = "This lists every package in the modules" +
+		" shown above and the tests that each package has." +
+		" The information in this report can be interpreted as follows."
+)
+
 // sortCol is a type alias for the TaggedEnum
 type sortCol = psetter.TaggedValue[rptmaker.ColID, rptmaker.SortWay]
 
 type OutputStyle string
 
 const (
-	styleReport  = "report"
-	styleDotFile = "dotfile"
+	styleReport   = "report"
+	styleDotFile  = "dotfile"
+	styleWhy      = "why"
+	stylePkgs     = "packages"
+	styleDeprec   = "deprecated"
+	styleMarkdown = "markdown"
+	styleHTML     = "html"
+	styleMermaid  = "mermaid"
+	stylePlantUML = "plantuml"
+	styleGraphML  = "graphml"
+	styleGEXF     = "gexf"
+	styleTree     = "tree"
+	styleRevTree  = "reverse-tree"
+	styleSBOM     = "sbom"
 )
 
 // prog holds program parameters, intermediate results and status
@@ -31,6 +58,9 @@ type prog struct {
 	hideDupLevels bool
 	showIntro     bool
 	showHeader    bool
+	strictScan    bool
+
+	scanOpts pkgScanOpts
 
 	sortBy []sortCol
 
@@ -39,12 +69,44 @@ type prog struct {
 	backFilter    map[string]bool
 	hideModules   map[string]bool
 
+	modFilterRE    map[string]bool
+	modFilterGlob  map[string]bool
+	backFilterRE   map[string]bool
+	backFilterGlob map[string]bool
+	hideModRE      map[string]bool
+	hideModGlob    map[string]bool
+
+	modFilterPats  modPatterns
+	backFilterPats modPatterns
+	hideModPats    modPatterns
+
+	filterDepth     int
+	backFilterDepth int
+
+	whyMods        []string
+	whyFrom        string
+	whyTo          string
+	whyViaExternal bool
+	whyMaxPaths    int
+
+	treeRoots        []string
+	reverseTreeRoots []string
+
 	columnsToShow []rptmaker.ColID
 
 	moduleFiles []string
 	mm          modMap
 	mInfo       []*modInfo
 
+	checkGoVersions bool
+	checkDirectives bool
+	checkGoSum      bool
+
+	useModCache    bool
+	modCacheDir    string
+	showExternal   bool
+	externalLevels bool
+
 	maxNameLen int
 
 	reportDigits int
@@ -52,7 +114,17 @@ type prog struct {
 
 	output OutputStyle
 
-	cols *rptmaker.Cols[*prog, *modInfo]
+	sbomNoTimestamp bool
+
+	cols    *rptmaker.Cols[*prog, *modInfo]
+	pkgCols *rptmaker.Cols[*prog, *PkgInfo]
+
+	maxPkgNameLen      int
+	maxPkgShortNameLen int
+	showPkgTests       bool
+
+	pkgColumnsToShow []rptmaker.ColID
+	pkgSortBy        []sortCol
 
 	dotFileDir string
 
@@ -70,18 +142,43 @@ func newProg() *prog {
 		sortBy:        []sortCol{{Value: ColLevel}, {Value: ColName}},
 		columnsToShow: []rptmaker.ColID{ColLevel, ColName, ColUseCountTotal},
 
+		pkgSortBy: []sortCol{{Value: PkgColModule}, {Value: PkgColImportPath}},
+		pkgColumnsToShow: []rptmaker.ColID{
+			PkgColModule,
+			PkgColImportPath,
+			PkgColName,
+			PkgColFiles,
+			PkgColLines,
+			PkgColTestLines,
+			PkgColTests,
+		},
+
 		modFilter:     map[string]bool{},
 		partialFilter: map[string]bool{},
 		backFilter:    map[string]bool{},
+		hideModules:   map[string]bool{},
+
+		filterDepth:     noDepthLimit,
+		backFilterDepth: noDepthLimit,
+
+		whyMaxPaths: dfltWhyMaxPaths,
 
 		mm: modMap{},
 
 		reportDigits: dfltDigitsToShow,
 
 		output: styleReport,
+
+		modCacheDir: defaultModCacheDir(),
+
+		scanOpts: pkgScanOpts{
+			goos:   build.Default.GOOS,
+			goarch: build.Default.GOARCH,
+		},
 	}
 
 	prog.cols = prog.populateCols()
+	prog.pkgCols = prog.populatePkgCols()
 
 	return prog
 }
@@ -96,72 +193,197 @@ func (prog *prog) setExitStatus(es int) {
 
 // run generates the module report
 func (prog *prog) run() {
-	if errMap := prog.mm.populate(prog.moduleFiles); errMap.HasErrors() {
+	errMap, scanErrMap := prog.mm.populate(prog.moduleFiles, prog.scanOpts)
+	if errMap.HasErrors() {
 		errMap.Report(os.Stderr, "")
 		prog.setExitStatus(1)
 
 		return
 	}
 
+	// errors found while scanning the packages are only fatal in strict
+	// mode; otherwise the files in error are skipped and the report is
+	// still produced but the exit status shows that errors were found.
+	if scanErrMap.HasErrors() {
+		scanErrMap.Report(os.Stderr, "")
+		prog.setExitStatus(1)
+
+		if prog.strictScan {
+			return
+		}
+	}
+
+	if prog.checkGoSum {
+		if errMap := prog.mm.readGoSums(); errMap.HasErrors() {
+			errMap.Report(os.Stderr, "")
+			prog.setExitStatus(1)
+
+			return
+		}
+	}
+
+	if prog.useModCache {
+		errMap := prog.mm.resolveExternal(prog.modCacheDir)
+		if errMap.HasErrors() {
+			errMap.Report(os.Stderr, "")
+			prog.setExitStatus(1)
+
+			return
+		}
+	}
+
 	prog.maxNameLen = prog.mm.findMaxNameLen()
 
-	prog.mm.calcLevels()
+	prog.mm.calcLevels(prog.externalLevels)
 	prog.mm.calcReqCount()
+	prog.mm.calcBlastRadius()
 
 	prog.expandModFilters()
 	prog.populateModInfo()
 
+	if prog.checkGoVersions && prog.reportGoVersionConflicts(os.Stderr) {
+		prog.setExitStatus(1)
+	}
+
+	if prog.checkDirectives && prog.reportDirectiveProblems(os.Stderr) {
+		prog.setExitStatus(1)
+	}
+
+	if prog.checkGoSum && prog.reportGoSumProblems(os.Stderr) {
+		prog.setExitStatus(1)
+	}
+
 	switch prog.output {
 	case styleReport:
-		prog.reportModuleInfo()
+		prog.reportModuleInfo(os.Stdout)
 	case styleDotFile:
 		prog.makeDotfile()
+	case styleWhy:
+		if errMap := prog.reportWhy(os.Stdout); errMap.HasErrors() {
+			errMap.Report(os.Stderr, "")
+			prog.setExitStatus(1)
+		}
+	case stylePkgs:
+		prog.reportPackages(os.Stdout, pkgReportIntro, prog.pkgColumnsToShow,
+			makeSortCols(prog.pkgSortBy))
+	case styleDeprec:
+		prog.reportDeprecated(os.Stdout)
+	case styleMarkdown:
+		prog.reportMarkdown(os.Stdout)
+	case styleHTML:
+		prog.reportHTML(os.Stdout)
+	case styleMermaid:
+		prog.writeMermaid(os.Stdout)
+	case stylePlantUML:
+		prog.writePlantUML(os.Stdout)
+	case styleGraphML:
+		prog.writeGraphML(os.Stdout)
+	case styleGEXF:
+		prog.writeGEXF(os.Stdout)
+	case styleTree:
+		prog.reportTree(os.Stdout)
+	case styleRevTree:
+		prog.reportReverseTree(os.Stdout)
+	case styleSBOM:
+		prog.writeSBOM(os.Stdout)
 	}
 }
 
-// applyBackFilters takes all the back filters and adds their
-// requirements to the set of filters
+// applyBackFilters takes all the back filters and adds the modules that
+// they require, directly or indirectly, to the set of filters. Only those
+// modules within the maximum back filter depth are added. If there is no
+// depth limit then the indirect requirements of the back filter modules are
+// also added.
 func (prog *prog) applyBackFilters() {
+	start := []*modInfo{}
+
 	for _, mi := range prog.mm {
 		if prog.backFilter[mi.Name] {
-			prog.modFilter[mi.Name] = true
+			start = append(start, mi)
+		}
+	}
 
-			for _, dr := range mi.DirectReqs {
-				prog.modFilter[dr.Name] = true
-			}
+	for name := range reachable(start, usesDirectly, prog.backFilterDepth) {
+		prog.modFilter[name] = true
+	}
 
-			for _, ir := range mi.IndirectReqs {
-				prog.modFilter[ir.Name] = true
-			}
+	if prog.backFilterDepth != noDepthLimit {
+		return
+	}
+
+	for _, mi := range start {
+		for _, ir := range mi.IndirectReqs {
+			prog.modFilter[ir.Name] = true
 		}
 	}
 }
 
-// applyForwardFilters takes all the filters and adds those modules that
-// require them (directly) to the set of filters
+// applyForwardFilters takes all the filters, including any modules matching
+// the partial filters, and adds those modules that require them, directly
+// or indirectly, to the set of filters. Only those modules within the
+// maximum filter depth are added.
 func (prog *prog) applyForwardFilters() {
+	start := []*modInfo{}
+
 	for _, mi := range prog.mm {
-		if prog.modFilter[mi.Name] {
-			for _, rb := range mi.ReqdByDirectly {
-				prog.modFilter[rb.Name] = true
-			}
-		} else {
-			if prog.matchPartialFilters(mi.Name) {
-				prog.modFilter[mi.Name] = true
-				for _, rb := range mi.ReqdByDirectly {
-					prog.modFilter[rb.Name] = true
-				}
-			}
+		if prog.modFilter[mi.Name] || prog.matchPartialFilters(mi.Name) {
+			start = append(start, mi)
 		}
 	}
+
+	for name := range reachable(start, usedByDirectly, prog.filterDepth) {
+		prog.modFilter[name] = true
+	}
+}
+
+// applyPatternFilters adds the names of any modules matching the filter
+// patterns to the corresponding filter maps.
+func (prog *prog) applyPatternFilters() {
+	for _, mi := range prog.mm {
+		if prog.modFilterPats.matches(mi.Name) {
+			prog.modFilter[mi.Name] = true
+		}
+
+		if prog.backFilterPats.matches(mi.Name) {
+			prog.backFilter[mi.Name] = true
+		}
+
+		if prog.hideModPats.matches(mi.Name) {
+			prog.hideModules[mi.Name] = true
+		}
+	}
+}
+
+// compilePatterns converts the regular expressions and glob patterns given
+// as parameters into the patterns used to match module names.
+func (prog *prog) compilePatterns() error {
+	return errors.Join(
+		prog.modFilterPats.addRegexps(prog.modFilterRE),
+		prog.modFilterPats.addGlobs(prog.modFilterGlob),
+		prog.backFilterPats.addRegexps(prog.backFilterRE),
+		prog.backFilterPats.addGlobs(prog.backFilterGlob),
+		prog.hideModPats.addRegexps(prog.hideModRE),
+		prog.hideModPats.addGlobs(prog.hideModGlob),
+	)
+}
+
+// filtersGiven returns true if any of the module filters or back filters
+// have been given. In this case only the modules selected by the filters
+// are shown, even if no module matches any of the filters.
+func (prog *prog) filtersGiven() bool {
+	return len(prog.modFilter) > 0 ||
+		len(prog.partialFilter) > 0 ||
+		len(prog.backFilter) > 0 ||
+		!prog.modFilterPats.isEmpty() ||
+		!prog.backFilterPats.isEmpty()
 }
 
 // expandModFilters takes the initial set of modFilters and adds all the
 // other modules that it is required by.
 func (prog *prog) expandModFilters() {
-	if len(prog.modFilter) == 0 &&
-		len(prog.partialFilter) == 0 &&
-		len(prog.backFilter) == 0 {
+	prog.applyPatternFilters()
+
+	if !prog.filtersGiven() {
 		return
 	}
 
@@ -169,12 +391,12 @@ func (prog *prog) expandModFilters() {
 	prog.applyBackFilters()
 }
 
-// makeSortCols converts the prog.sortBy slice into a slice of
+// makeSortCols converts the sortBy slice into a slice of
 // [rptmaker.SortColumns].
-func (prog *prog) makeSortCols() []rptmaker.SortColumn {
-	sortCols := make([]rptmaker.SortColumn, 0, len(prog.sortBy))
+func makeSortCols(sortBy []sortCol) []rptmaker.SortColumn {
+	sortCols := make([]rptmaker.SortColumn, 0, len(sortBy))
 
-	for _, sc := range prog.sortBy {
+	for _, sc := range sortBy {
 		sortCols = append(sortCols, rptmaker.MakeSortColumn(sc.Value, sc.Tags))
 	}
 
@@ -221,8 +443,9 @@ func (prog *prog) matchPartialFilters(moduleName string) bool {
 	}
 }
 
-// headerOptFuncs returns a slice of header option functions
-func (prog *prog) headerOptFuncs() []col.HdrOptionFunc {
+// headerOptFuncs returns a slice of header option functions. The introFunc
+// will be called before the header is printed if the intro is to be shown.
+func (prog *prog) headerOptFuncs(introFunc col.PreHdrFunc) []col.HdrOptionFunc {
 	hdrOpts := []col.HdrOptionFunc{}
 
 	if !prog.showHeader {
@@ -230,9 +453,7 @@ func (prog *prog) headerOptFuncs() []col.HdrOptionFunc {
 	}
 
 	if prog.showIntro {
-		hdrOpts = append(hdrOpts,
-			col.HdrOptPreHdrFunc(makeReportIntroFunc(prog)),
-		)
+		hdrOpts = append(hdrOpts, col.HdrOptPreHdrFunc(introFunc))
 	}
 
 	if prog.headerRepeat > 0 {
@@ -246,13 +467,16 @@ func (prog *prog) headerOptFuncs() []col.HdrOptionFunc {
 
 // makeReportIntroFunc returns a function that can be supplied when
 // constructing a report header and will be called before the header is
-// printed.
-func makeReportIntroFunc(prog *prog) col.PreHdrFunc {
+// printed. It will print the intro text followed by a description of each
+// of the columns being shown.
+func makeReportIntroFunc[T any](
+	intro string, cols *rptmaker.Cols[*prog, T], colIDs []rptmaker.ColID,
+) col.PreHdrFunc {
 	const colNameIndent = 4
 
 	maxColNameLen := 0
 
-	for _, c := range prog.columnsToShow {
+	for _, c := range colIDs {
 		maxColNameLen = max(maxColNameLen, len(c))
 	}
 
@@ -264,13 +488,10 @@ func makeReportIntroFunc(prog *prog) col.PreHdrFunc {
 
 		twc := twrap.NewTWConfOrPanic(twrap.SetWriter(w))
 
-		twc.Wrap("This gives information about a collection of modules"+
-			" and how they relate to one another."+
-			" The information in this report can be interpreted as follows.",
-			0)
+		twc.Wrap(intro, 0)
 
-		for _, cid := range prog.columnsToShow {
-			ci, err := prog.cols.GetReportableColInfo(cid)
+		for _, cid := range colIDs {
+			ci, err := cols.GetReportableColInfo(cid)
 			if err != nil {
 				twc.Println(err)
 				continue
@@ -296,11 +517,11 @@ func makeReportIntroFunc(prog *prog) col.PreHdrFunc {
 //
 // The module name is in the hideModules map
 func (prog *prog) skipModInfo(mi *modInfo) bool {
-	if mi.Loc == nil {
+	if mi.Loc == nil && !prog.showExternal {
 		return true
 	}
 
-	if len(prog.modFilter) > 0 && !prog.modFilter[mi.Name] {
+	if prog.filtersGiven() && !prog.modFilter[mi.Name] {
 		return true
 	}
 
@@ -353,23 +574,106 @@ func (prog *prog) makeDotfile() {
 	fmt.Println("see: ", f.Name())
 }
 
-// reportModuleInfo prints the module information
-func (prog *prog) reportModuleInfo() {
+// reportModuleInfo prints the module information to the writer
+func (prog *prog) reportModuleInfo(w io.Writer) {
 	// recreate the cols with the prog value post param parsing
 	prog.cols = prog.populateCols()
 
 	reporter, err := prog.cols.MakeReport(prog,
-		os.Stdout,
+		w,
 		prog.columnsToShow,
-		prog.headerOptFuncs()...)
+		prog.headerOptFuncs(
+			makeReportIntroFunc(modReportIntro,
+				prog.cols, prog.columnsToShow))...)
 	if err != nil {
 		fmt.Println("Couldn't make the report:", err)
 		return
 	}
 
-	if err = reporter.Print(prog.mInfo, prog.makeSortCols()); err != nil {
+	if err = reporter.Print(prog.mInfo, makeSortCols(prog.sortBy)); err != nil {
 		fmt.Println("Couldn't print the report:", err)
 
 		return
 	}
+
+	if prog.showPkgTests {
+		fmt.Fprintln(w)
+		prog.reportPackages(w, pkgTestsReportIntro, pkgTestCols,
+			[]rptmaker.SortColumn{
+				{ID: PkgColModule},
+				{ID: PkgColImportPath},
+			})
+	}
+}
+
+// sortModInfo sorts the modules being reported according to the sort
+// columns given by the user
+func (prog *prog) sortModInfo() error {
+	sortCols := makeSortCols(prog.sortBy)
+	if len(sortCols) == 0 {
+		return nil
+	}
+
+	reporter, err := prog.cols.MakeReport(prog, io.Discard, prog.columnsToShow)
+	if err != nil {
+		return err
+	}
+
+	cf, err := reporter.MkCmpFunc(sortCols)
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(prog.mInfo, cf)
+
+	return nil
+}
+
+// setMaxPkgNameLens finds the length of the longest package import name,
+// after the prefix has been stripped, and of the longest package name in the
+// modules being reported.
+func (prog *prog) setMaxPkgNameLens() {
+	prog.maxPkgNameLen, prog.maxPkgShortNameLen = 0, 0
+
+	for _, mi := range prog.mInfo {
+		for _, pi := range mi.Packages {
+			prog.maxPkgNameLen = max(
+				len(strings.TrimPrefix(pi.ImportName, prog.stripPrefix)),
+				prog.maxPkgNameLen)
+			prog.maxPkgShortNameLen = max(len(pi.Name),
+				prog.maxPkgShortNameLen)
+		}
+	}
+}
+
+// reportPackages prints a report to the writer with one line per package
+// from the modules being reported. The report shows the given columns and
+// is sorted according to the sortCols.
+func (prog *prog) reportPackages(
+	w io.Writer,
+	intro string, colIDs []rptmaker.ColID, sortCols []rptmaker.SortColumn,
+) {
+	prog.setMaxPkgNameLens()
+	prog.pkgCols = prog.populatePkgCols()
+
+	pkgs := []*PkgInfo{}
+	for _, mi := range prog.mInfo {
+		pkgs = append(pkgs, mi.sortedPackages()...)
+	}
+
+	reporter, err := prog.pkgCols.MakeReport(prog,
+		w,
+		colIDs,
+		prog.headerOptFuncs(
+			makeReportIntroFunc(intro, prog.pkgCols, colIDs))...)
+	if err != nil {
+		fmt.Println("Couldn't make the package report:", err)
+		return
+	}
+
+	if err = reporter.Print(pkgs, sortCols); err != nil {
+		fmt.Println("Couldn't print the package report:", err)
+
+		return
+	}
 }
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nickwells/col.mod/v6/rptmaker"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestExpandModFilters(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		filter          []string
+		filterDepth     int
+		backFilter      []string
+		backFilterDepth int
+		expFilter       []string
+	}{
+		{
+			ID:              testhelper.MkID("no filters"),
+			filterDepth:     noDepthLimit,
+			backFilterDepth: noDepthLimit,
+			expFilter:       []string{},
+		},
+		{
+			ID:              testhelper.MkID("forward, no limit"),
+			filter:          []string{"example.com/A"},
+			filterDepth:     noDepthLimit,
+			backFilterDepth: noDepthLimit,
+			expFilter: []string{
+				"example.com/A",
+				"example.com/B",
+				"example.com/C",
+				"example.com/D",
+			},
+		},
+		{
+			ID:              testhelper.MkID("forward, depth 2"),
+			filter:          []string{"example.com/A"},
+			filterDepth:     2,
+			backFilterDepth: noDepthLimit,
+			expFilter: []string{
+				"example.com/A",
+				"example.com/B",
+				"example.com/C",
+			},
+		},
+		{
+			ID:              testhelper.MkID("back, no limit"),
+			backFilter:      []string{"example.com/C"},
+			filterDepth:     noDepthLimit,
+			backFilterDepth: noDepthLimit,
+			expFilter: []string{
+				"example.com/A",
+				"example.com/B",
+				"example.com/C",
+				"example.com/X",
+			},
+		},
+		{
+			ID:              testhelper.MkID("back, depth 1"),
+			backFilter:      []string{"example.com/D"},
+			filterDepth:     noDepthLimit,
+			backFilterDepth: 1,
+			expFilter: []string{
+				"example.com/C",
+				"example.com/D",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.filterDepth = tc.filterDepth
+		prog.backFilterDepth = tc.backFilterDepth
+
+		for _, name := range tc.filter {
+			prog.modFilter[name] = true
+		}
+
+		for _, name := range tc.backFilter {
+			prog.backFilter[name] = true
+		}
+
+		prog.expandModFilters()
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "filter",
+			slices.Sorted(maps.Keys(prog.modFilter)), tc.expFilter)
+	}
+}
+
+func TestUnmatchedFilters(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		setFilters func(prog *prog)
+		expShown   int
+	}{
+		{
+			ID:         testhelper.MkID("no filters"),
+			setFilters: func(_ *prog) {},
+			expShown:   4,
+		},
+		{
+			ID: testhelper.MkID("unmatched name"),
+			setFilters: func(prog *prog) {
+				prog.modFilter["example.com/Q"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched partial name"),
+			setFilters: func(prog *prog) {
+				prog.partialFilter["Q"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched back filter name"),
+			setFilters: func(prog *prog) {
+				prog.backFilter["example.com/Q"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched regexp"),
+			setFilters: func(prog *prog) {
+				prog.modFilterRE["example.com/Q.*"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched glob"),
+			setFilters: func(prog *prog) {
+				prog.modFilterGlob["example.com/Q*"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched back filter regexp"),
+			setFilters: func(prog *prog) {
+				prog.backFilterRE["example.com/Q.*"] = true
+			},
+		},
+		{
+			ID: testhelper.MkID("unmatched back filter glob"),
+			setFilters: func(prog *prog) {
+				prog.backFilterGlob["example.com/Q*"] = true
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.modFilterRE = map[string]bool{}
+		prog.modFilterGlob = map[string]bool{}
+		prog.backFilterRE = map[string]bool{}
+		prog.backFilterGlob = map[string]bool{}
+
+		tc.setFilters(prog)
+
+		if err := prog.compilePatterns(); err != nil {
+			t.Fatalf("%s: cannot compile the patterns: %s", tc.IDStr(), err)
+		}
+
+		prog.expandModFilters()
+		prog.populateModInfo()
+
+		testhelper.DiffInt(t, tc.IDStr(), "modules shown",
+			len(prog.mInfo), tc.expShown)
+	}
+}
+
+func TestReportModuleInfoPkgTests(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		showPkgTests bool
+		expOut       string
+	}{
+		{
+			ID: testhelper.MkID("module report only"),
+			expOut: "            ----Test---- Untested\n" +
+				"Module name   LoC  Ratio Packages\n" +
+				"===========   ===  ===== ========\n" +
+				"A               5   0.17        1\n" +
+				"B/v2           30  10.00        0\n",
+		},
+		{
+			ID:           testhelper.MkID("with the package tests"),
+			showPkgTests: true,
+			expOut: "            ----Test---- Untested\n" +
+				"Module name   LoC  Ratio Packages\n" +
+				"===========   ===  ===== ========\n" +
+				"A               5   0.17        1\n" +
+				"B/v2           30  10.00        0\n" +
+				"\n" +
+				"                                 Test                  \n" +
+				"Module name Import path     LoC   LoC Tests            \n" +
+				"=========== ===========     ===   === =====            \n" +
+				"A           A                10     5 internal         \n" +
+				"A           A/cmd/longcmd    20     0 none             \n" +
+				"B/v2        B/v2              3    30 external         \n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := mkTestPkgProg(t)
+		prog.stripPrefix = "example.com/"
+		prog.showPkgTests = tc.showPkgTests
+		prog.columnsToShow = []rptmaker.ColID{
+			ColName, ColTestLines, ColTestRatio, ColUntestedPkgs,
+		}
+
+		for _, mi := range prog.mInfo {
+			for _, pi := range mi.Packages {
+				mi.LinesOfCode += pi.FilesLoC
+				mi.TestLinesOfCode += pi.TestFilesLoC
+			}
+		}
+
+		var buf bytes.Buffer
+
+		prog.reportModuleInfo(&buf)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
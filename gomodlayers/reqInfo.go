@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ReqInfo records the details of a requirement of one module by another as
+// given in the go.mod file of the requiring module. The hashes are taken
+// from the go.sum file of the requiring module, if it has been read.
+type ReqInfo struct {
+	Mod          *modInfo
+	Version      string
+	Indirect     bool
+	Line         int
+	GoSumHash    string
+	GoModSumHash string
+}
+
+// newReqInfo creates a new ReqInfo for the required module from the
+// details in the require line.
+func newReqInfo(reqdMI *modInfo, req *modfile.Require) *ReqInfo {
+	ri := &ReqInfo{
+		Mod:      reqdMI,
+		Version:  req.Mod.Version,
+		Indirect: req.Indirect,
+	}
+
+	if req.Syntax != nil {
+		ri.Line = req.Syntax.Start.Line
+	}
+
+	return ri
+}
+
+// kind returns a string describing the type of the requirement
+func (ri ReqInfo) kind() string {
+	if ri.Indirect {
+		return "indirect"
+	}
+
+	return "direct"
+}
+
+// reqLocation returns a string giving the location of the require line in
+// the go.mod file of the requiring module.
+func (mi *modInfo) reqLocation(ri *ReqInfo) string {
+	return mi.lineLocation(ri.Line)
+}
+
+// lineLocation returns a string giving the location of the given line in
+// the go.mod file of the module.
+func (mi *modInfo) lineLocation(line int) string {
+	if mi.Loc == nil {
+		return "unknown location"
+	}
+
+	return fmt.Sprintf("%s:%d", mi.Loc.Source(), line)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// cdxComponent is a CycloneDX component
+type cdxComponent struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl"`
+}
+
+// cdxDependency is a CycloneDX dependency relationship
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cdxTool is a CycloneDX description of the tool which generated the BOM
+type cdxTool struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// cdxMetadata is the CycloneDX metadata
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp,omitempty"`
+	Tools     struct {
+		Components []cdxTool `json:"components"`
+	} `json:"tools"`
+}
+
+// cdxBOM is a CycloneDX software bill of materials
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+// requiredVersion returns the highest version of the named module required
+// by any module in the collection. This is the version that minimal version
+// selection would choose if all the modules were built together. It returns
+// the empty string if no module gives a version.
+func (mm modMap) requiredVersion(name string) string {
+	version := ""
+
+	for _, mi := range mm {
+		if ri, ok := mi.Reqs[name]; ok {
+			version = semver.Max(version, ri.Version)
+		}
+	}
+
+	return version
+}
+
+// develVersion is the version given to a module in the collection which no
+// module requires and so has no version. This matches the version that the
+// go command reports for the main module.
+const develVersion = "(devel)"
+
+// modPURL returns the package URL for the given version of the module. The
+// develVersion is not a real version and so is left out.
+func modPURL(name, version string) string {
+	purl := "pkg:golang/" + name
+	if version != "" && version != develVersion {
+		purl += "@" + version
+	}
+
+	return purl
+}
+
+// mkSBOM returns the CycloneDX BOM for the modules being shown and the
+// external modules that they require. The dependencies of each module are
+// all of its requirements, direct and indirect. If the timestamp is the zero
+// time it is left out so that the BOM is reproducible.
+func (prog *prog) mkSBOM(timestamp time.Time) cdxBOM {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		Version:      1,
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	if !timestamp.IsZero() {
+		bom.Metadata.Timestamp = timestamp.UTC().Format(time.RFC3339)
+	}
+
+	bom.Metadata.Tools.Components = []cdxTool{
+		{Type: "application", Name: "gomodlayers"},
+	}
+
+	nodes, edges := prog.exportGraph()
+
+	for _, mi := range nodes {
+		version := prog.mm.requiredVersion(mi.Name)
+		if version == "" && mi.Loc != nil {
+			version = develVersion
+		}
+
+		bom.Components = append(bom.Components, cdxComponent{
+			Type:    "library",
+			BOMRef:  mi.Name,
+			Name:    mi.Name,
+			Version: version,
+			PURL:    modPURL(mi.Name, version),
+		})
+
+		dep := cdxDependency{Ref: mi.Name, DependsOn: []string{}}
+
+		for _, e := range edges {
+			if e.from == mi {
+				dep.DependsOn = append(dep.DependsOn, e.to.Name)
+			}
+		}
+
+		slices.Sort(dep.DependsOn)
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	return bom
+}
+
+// writeSBOM writes a CycloneDX software bill of materials in JSON format.
+// The BOM is timestamped with the current time unless the timestamp is to
+// be left out.
+func (prog *prog) writeSBOM(w io.Writer) {
+	timestamp := time.Now()
+	if prog.sbomNoTimestamp {
+		timestamp = time.Time{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(prog.mkSBOM(timestamp)); err != nil {
+		fmt.Fprintln(os.Stderr, "Couldn't write the SBOM:", err)
+		prog.setExitStatus(1)
+	}
+}
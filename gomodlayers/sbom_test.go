@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkTestSBOMProg returns a prog for testing the SBOM. Module C is required
+// by no other module and module D, and so Y, is hidden.
+func mkTestSBOMProg(t *testing.T) *prog {
+	t.Helper()
+
+	prog := newProg()
+	prog.mm = mkTestModMap(t,
+		"module example.com/A\n",
+		"module example.com/B\n"+
+			"require (\n"+
+			"\texample.com/A v1.2.0\n"+
+			"\texample.com/X v0.1.0\n"+
+			")\n",
+		"module example.com/C\n"+
+			"require (\n"+
+			"\texample.com/B v1.0.0\n"+
+			"\texample.com/A v1.3.0 // indirect\n"+
+			")\n",
+		"module example.com/D\n"+
+			"require example.com/Y v0.2.0\n",
+	)
+	prog.hideModules["example.com/D"] = true
+	prog.populateModInfo()
+
+	return prog
+}
+
+func TestMkSBOM(t *testing.T) {
+	prog := mkTestSBOMProg(t)
+
+	bom := prog.mkSBOM(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	out, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		t.Fatalf("cannot marshal the SBOM: %s", err)
+	}
+
+	expOut := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "version": 1,
+  "metadata": {
+    "timestamp": "2024-01-02T03:04:05Z",
+    "tools": {
+      "components": [
+        {
+          "type": "application",
+          "name": "gomodlayers"
+        }
+      ]
+    }
+  },
+  "components": [
+    {
+      "type": "library",
+      "bom-ref": "example.com/A",
+      "name": "example.com/A",
+      "version": "v1.3.0",
+      "purl": "pkg:golang/example.com/A@v1.3.0"
+    },
+    {
+      "type": "library",
+      "bom-ref": "example.com/B",
+      "name": "example.com/B",
+      "version": "v1.0.0",
+      "purl": "pkg:golang/example.com/B@v1.0.0"
+    },
+    {
+      "type": "library",
+      "bom-ref": "example.com/C",
+      "name": "example.com/C",
+      "version": "(devel)",
+      "purl": "pkg:golang/example.com/C"
+    },
+    {
+      "type": "library",
+      "bom-ref": "example.com/X",
+      "name": "example.com/X",
+      "version": "v0.1.0",
+      "purl": "pkg:golang/example.com/X@v0.1.0"
+    }
+  ],
+  "dependencies": [
+    {
+      "ref": "example.com/A",
+      "dependsOn": []
+    },
+    {
+      "ref": "example.com/B",
+      "dependsOn": [
+        "example.com/A",
+        "example.com/X"
+      ]
+    },
+    {
+      "ref": "example.com/C",
+      "dependsOn": [
+        "example.com/A",
+        "example.com/B"
+      ]
+    },
+    {
+      "ref": "example.com/X",
+      "dependsOn": []
+    }
+  ]
+}`
+
+	testhelper.DiffString(t, "SBOM", "output", string(out), expOut)
+}
+
+func TestWriteSBOMNoTimestamp(t *testing.T) {
+	prog := mkTestSBOMProg(t)
+	prog.sbomNoTimestamp = true
+
+	var first, second bytes.Buffer
+
+	prog.writeSBOM(&first)
+	prog.writeSBOM(&second)
+
+	testhelper.DiffInt(t, "SBOM", "exit status", prog.exitStatus, 0)
+	testhelper.DiffBool(t, "SBOM", "has a timestamp",
+		strings.Contains(first.String(), `"timestamp"`), false)
+	testhelper.DiffString(t, "SBOM", "repeated output",
+		second.String(), first.String())
+}
@@ -0,0 +1,50 @@
+package main
+
+import "math"
+
+// afferentCoupling returns the number of modules in the collection which
+// use this module directly. This is the Ca value from Robert Martin's
+// package stability metrics.
+func (mi *modInfo) afferentCoupling() int {
+	return len(mi.ReqdByDirectly)
+}
+
+// efferentCoupling returns the number of modules which this module uses
+// directly, whether or not they are in the collection. This is the Ce value
+// from Robert Martin's package stability metrics.
+func (mi *modInfo) efferentCoupling() int {
+	return len(mi.DirectReqs)
+}
+
+// instability returns the ratio of the efferent coupling to the total
+// coupling: I = Ce/(Ca+Ce). A value of 0 indicates a maximally stable
+// module (it is used but uses nothing) and 1 a maximally unstable one. A
+// module with no coupling at all is given an instability of 0.
+func (mi *modInfo) instability() float64 {
+	ca, ce := mi.afferentCoupling(), mi.efferentCoupling()
+	if ca+ce == 0 {
+		return 0
+	}
+
+	return float64(ce) / float64(ca+ce)
+}
+
+// abstractness returns the proportion of the exported types in the module
+// which are interfaces. A module with no exported types is given an
+// abstractness of 0.
+func (mi *modInfo) abstractness() float64 {
+	if mi.API.Types == 0 {
+		return 0
+	}
+
+	return float64(mi.API.Interfaces) / float64(mi.API.Types)
+}
+
+// mainSeqDistance returns the distance of the module from the main
+// sequence: D = |A + I - 1|. Modules near the main sequence balance
+// abstractness and stability. A value near 1 indicates a module which is
+// either stable and concrete (and so hard to change) or unstable and
+// abstract (and so of little use).
+func (mi *modInfo) mainSeqDistance() float64 {
+	return math.Abs(mi.abstractness() + mi.instability() - 1)
+}
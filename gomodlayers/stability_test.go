@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestStabilityMetrics(t *testing.T) {
+	const epsilon = 1e-9
+
+	mm := testModMapABCD(t)
+	mm["example.com/B"].API = APICounts{Types: 4, Interfaces: 1}
+
+	for name, exp := range map[string]struct {
+		ca, ce int
+		i, a   float64
+		d      float64
+	}{
+		"example.com/A": {ca: 1, ce: 0, i: 0, a: 0, d: 1},
+		"example.com/B": {ca: 1, ce: 1, i: 0.5, a: 0.25, d: 0.25},
+		"example.com/C": {ca: 1, ce: 2, i: 2.0 / 3.0, a: 0, d: 1.0 / 3.0},
+		"example.com/D": {ca: 0, ce: 1, i: 1, a: 0, d: 0},
+	} {
+		mi := mm[name]
+		testhelper.DiffInt(t, name, "Ca", mi.afferentCoupling(), exp.ca)
+		testhelper.DiffInt(t, name, "Ce", mi.efferentCoupling(), exp.ce)
+		testhelper.DiffFloat(t, name, "I", mi.instability(), exp.i, epsilon)
+		testhelper.DiffFloat(t, name, "A", mi.abstractness(), exp.a, epsilon)
+		testhelper.DiffFloat(t, name, "D", mi.mainSeqDistance(), exp.d,
+			epsilon)
+	}
+}
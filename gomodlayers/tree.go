@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// treePrinter holds the details needed to print a tree of modules
+type treePrinter struct {
+	w        io.Writer
+	next     modEdgeFunc
+	label    func(*modInfo) string
+	expanded map[string]bool
+}
+
+// printTree prints the tree of modules starting at the root. Any module
+// whose subtree has already been printed is marked as "(see above)" and
+// its subtree is not printed again.
+func (tp *treePrinter) printTree(root *modInfo) {
+	if tp.expanded[root.Name] && len(tp.next(root)) > 0 {
+		fmt.Fprintln(tp.w, tp.label(root)+" (see above)")
+		return
+	}
+
+	fmt.Fprintln(tp.w, tp.label(root))
+
+	tp.expanded[root.Name] = true
+	tp.printChildren(root, "")
+}
+
+// printChildren prints the subtrees of the module's children, with each
+// line preceded by the indent
+func (tp *treePrinter) printChildren(mi *modInfo, indent string) {
+	const (
+		branch     = "├── "
+		lastBranch = "└── "
+		stem       = "│   "
+		noStem     = "    "
+	)
+
+	children := tp.next(mi)
+
+	for i, c := range children {
+		br, childIndent := branch, indent+stem
+		if i == len(children)-1 {
+			br, childIndent = lastBranch, indent+noStem
+		}
+
+		if tp.expanded[c.Name] && len(tp.next(c)) > 0 {
+			fmt.Fprintln(tp.w, indent+br+tp.label(c)+" (see above)")
+			continue
+		}
+
+		fmt.Fprintln(tp.w, indent+br+tp.label(c))
+
+		tp.expanded[c.Name] = true
+		tp.printChildren(c, childIndent)
+	}
+}
+
+// printTrees prints a tree for each of the roots, separated by blank lines.
+// A subtree printed for an earlier root is not printed again.
+func (tp *treePrinter) printTrees(roots []*modInfo) {
+	for i, root := range roots {
+		if i > 0 {
+			fmt.Fprintln(tp.w)
+		}
+
+		tp.printTree(root)
+	}
+}
+
+// treeRootMods returns the modules named as the roots of the trees. If any
+// of the names is not a module in the collection, or required by one, an
+// error is reported and nil is returned.
+func (prog *prog) treeRootMods(names []string) []*modInfo {
+	roots := []*modInfo{}
+
+	for _, name := range names {
+		mi, ok := prog.mm[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr,
+				"module %q is not in the collection of modules\n", name)
+			prog.setExitStatus(1)
+
+			return nil
+		}
+
+		roots = append(roots, mi)
+	}
+
+	return roots
+}
+
+// topLevelMods returns those modules being shown which are not required by
+// any other module being shown, sorted by name
+func (prog *prog) topLevelMods() []*modInfo {
+	roots := []*modInfo{}
+
+	for _, mi := range prog.mInfo {
+		if !slices.ContainsFunc(usedBy(mi), func(ub *modInfo) bool {
+			return !prog.skipModInfo(ub)
+		}) {
+			roots = append(roots, mi)
+		}
+	}
+
+	slices.SortFunc(roots, cmpModNames)
+
+	return roots
+}
+
+// skipTreeMod returns true if the module should not be shown in a tree.
+// Hidden modules are not shown and, if any filters have been given, nor are
+// the modules not selected by them. Unlike the other reports, external
+// modules are shown.
+func (prog *prog) skipTreeMod(mi *modInfo) bool {
+	if prog.hideModules[mi.Name] {
+		return true
+	}
+
+	return prog.filtersGiven() && !prog.modFilter[mi.Name]
+}
+
+// treeChildren returns the function giving the children of a module in a
+// tree. The modules which should not be shown in a tree are left out.
+func (prog *prog) treeChildren(next modEdgeFunc) modEdgeFunc {
+	return func(mi *modInfo) []*modInfo {
+		return slices.DeleteFunc(slices.Clone(next(mi)), prog.skipTreeMod)
+	}
+}
+
+// reportTree prints the tree of the direct requirements of the chosen root
+// modules or, if none were chosen, of the top-level modules.
+func (prog *prog) reportTree(w io.Writer) {
+	roots := prog.topLevelMods()
+	if len(prog.treeRoots) > 0 {
+		roots = prog.treeRootMods(prog.treeRoots)
+	}
+
+	tp := &treePrinter{
+		w:        w,
+		next:     prog.treeChildren(usesDirectly),
+		label:    prog.displayName,
+		expanded: map[string]bool{},
+	}
+	tp.printTrees(roots)
+}
+
+// levelLabel returns the module name as it should be shown followed by the
+// level of the module. External modules have no level.
+func (prog *prog) levelLabel(mi *modInfo) string {
+	if mi.Loc == nil {
+		return prog.displayName(mi)
+	}
+
+	return prog.displayName(mi) + " (level " + strconv.Itoa(mi.Level) + ")"
+}
+
+// reportReverseTree prints the tree of the modules which directly require
+// each of the chosen modules, and the modules which require them and so on
+// up to the top-level modules. Each module is shown with its level.
+func (prog *prog) reportReverseTree(w io.Writer) {
+	tp := &treePrinter{
+		w:        w,
+		next:     prog.treeChildren(usedByDirectly),
+		label:    prog.levelLabel,
+		expanded: map[string]bool{},
+	}
+	tp.printTrees(prog.treeRootMods(prog.reverseTreeRoots))
+}
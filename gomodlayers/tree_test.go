@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReportTree(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		roots      []string
+		hide       []string
+		filter     []string
+		backFilter []string
+		expOut     string
+	}{
+		{
+			ID: testhelper.MkID("top-level modules"),
+			expOut: "D\n" +
+				"└── C\n" +
+				"    ├── B\n" +
+				"    │   └── A\n" +
+				"    └── X (external)\n",
+		},
+		{
+			ID:    testhelper.MkID("chosen roots"),
+			roots: []string{"example.com/C", "example.com/B"},
+			expOut: "C\n" +
+				"├── B\n" +
+				"│   └── A\n" +
+				"└── X (external)\n" +
+				"\n" +
+				"B (see above)\n",
+		},
+		{
+			ID:    testhelper.MkID("hidden module"),
+			roots: []string{"example.com/C"},
+			hide:  []string{"example.com/X"},
+			expOut: "C\n" +
+				"└── B\n" +
+				"    └── A\n",
+		},
+		{
+			ID:     testhelper.MkID("filtered"),
+			filter: []string{"example.com/B"},
+			expOut: "D\n" +
+				"└── C\n" +
+				"    └── B\n",
+		},
+		{
+			ID:         testhelper.MkID("back filtered"),
+			backFilter: []string{"example.com/C"},
+			expOut: "C\n" +
+				"├── B\n" +
+				"│   └── A\n" +
+				"└── X (external)\n",
+		},
+		{
+			ID:     testhelper.MkID("filter matching nothing"),
+			filter: []string{"example.com/Q"},
+			expOut: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.stripPrefix = "example.com/"
+		prog.treeRoots = tc.roots
+
+		for _, h := range tc.hide {
+			prog.hideModules[h] = true
+		}
+
+		for _, f := range tc.filter {
+			prog.modFilter[f] = true
+		}
+
+		for _, f := range tc.backFilter {
+			prog.backFilter[f] = true
+		}
+
+		prog.expandModFilters()
+		prog.populateModInfo()
+
+		var buf bytes.Buffer
+
+		prog.reportTree(&buf)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
+
+func TestReportReverseTree(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		roots      []string
+		filter     []string
+		backFilter []string
+		expOut     string
+	}{
+		{
+			ID:    testhelper.MkID("internal module"),
+			roots: []string{"example.com/A"},
+			expOut: "A (level 0)\n" +
+				"└── B (level 1)\n" +
+				"    └── C (level 2)\n" +
+				"        └── D (level 3)\n",
+		},
+		{
+			ID:    testhelper.MkID("external module"),
+			roots: []string{"example.com/X", "example.com/C"},
+			expOut: "X (external)\n" +
+				"└── C (level 2)\n" +
+				"    └── D (level 3)\n" +
+				"\n" +
+				"C (level 2) (see above)\n",
+		},
+		{
+			ID:         testhelper.MkID("back filtered"),
+			roots:      []string{"example.com/A"},
+			backFilter: []string{"example.com/C"},
+			expOut: "A (level 0)\n" +
+				"└── B (level 1)\n" +
+				"    └── C (level 2)\n",
+		},
+		{
+			ID:     testhelper.MkID("filtered"),
+			roots:  []string{"example.com/A"},
+			filter: []string{"example.com/C"},
+			expOut: "A (level 0)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.stripPrefix = "example.com/"
+		prog.reverseTreeRoots = tc.roots
+		for _, f := range tc.filter {
+			prog.modFilter[f] = true
+		}
+
+		for _, f := range tc.backFilter {
+			prog.backFilter[f] = true
+		}
+
+		prog.expandModFilters()
+		prog.populateModInfo()
+
+		var buf bytes.Buffer
+
+		prog.reportReverseTree(&buf)
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
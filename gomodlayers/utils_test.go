@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nickwells/location.mod/location"
+)
+
+// mkTestModMap constructs a modMap from the supplied go.mod file contents.
+// Each go.mod file is given a distinct location. Any parse errors are
+// reported as fatal errors.
+func mkTestModMap(t *testing.T, goModFiles ...string) modMap {
+	t.Helper()
+
+	mm := modMap{}
+
+	for i, contents := range goModFiles {
+		loc := location.New(fmt.Sprintf("test%d/go.mod", i))
+
+		if _, err := parseGoModFile(mm, []byte(contents), loc); err != nil {
+			t.Fatalf("cannot parse go.mod file %d: %s", i, err)
+		}
+	}
+
+	mm.sortReqdByNames()
+	mm.calcLevels(false)
+	mm.calcReqCount()
+
+	return mm
+}
+
+// testModMapABCD returns a modMap with the following modules:
+//
+//	A - uses nothing
+//	B - uses A directly
+//	C - uses B directly, A indirectly, and the external module X
+//	D - uses C directly, B and A indirectly
+func testModMapABCD(t *testing.T) modMap {
+	t.Helper()
+
+	return mkTestModMap(t,
+		"module example.com/A\n",
+		"module example.com/B\n"+
+			"require example.com/A v1.0.0\n",
+		"module example.com/C\n"+
+			"require (\n"+
+			"\texample.com/B v1.0.0\n"+
+			"\texample.com/X v1.0.0\n"+
+			"\texample.com/A v1.0.0 // indirect\n"+
+			")\n",
+		"module example.com/D\n"+
+			"require (\n"+
+			"\texample.com/C v1.0.0\n"+
+			"\texample.com/A v1.0.0 // indirect\n"+
+			"\texample.com/B v1.0.0 // indirect\n"+
+			")\n",
+	)
+}
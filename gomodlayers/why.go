@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/errutil.mod/errutil"
+)
+
+// dfltWhyMaxPaths is the default maximum number of requirement paths shown
+const dfltWhyMaxPaths = 20
+
+// reqPath is a chain of requirements leading from one module to another
+type reqPath []*ReqInfo
+
+// whyFinder holds the details needed to find the requirement paths between
+// two modules
+type whyFinder struct {
+	to          *modInfo
+	viaExternal bool
+	maxPaths    int
+	canReach    map[string]int
+	onPath      map[string]bool
+	paths       []reqPath
+}
+
+// findReqPaths returns the chains of requirements leading from the from
+// module to the to module. Requirements are followed whether they are
+// direct or indirect. A path will only go through an external module if
+// viaExternal is true; an external module only has requirements if it has
+// been looked up in the module cache. The paths are returned in a
+// consistent order. At most maxPaths+1 paths are returned so that the
+// caller can tell if there are more than maxPaths paths; the number of
+// paths can grow exponentially with the number of modules.
+func findReqPaths(from, to *modInfo, viaExternal bool, maxPaths int) []reqPath {
+	wf := &whyFinder{
+		to:          to,
+		viaExternal: viaExternal,
+		maxPaths:    maxPaths,
+		onPath:      map[string]bool{},
+	}
+
+	// only those modules from which the target can be reached need to be
+	// searched
+	wf.canReach = reachable([]*modInfo{to},
+		func(mi *modInfo) []*modInfo {
+			return slices.DeleteFunc(usedBy(mi), func(rbmi *modInfo) bool {
+				return rbmi.Loc == nil && !viaExternal
+			})
+		},
+		noDepthLimit)
+
+	if from != to {
+		wf.search(from, reqPath{})
+	}
+
+	return wf.paths
+}
+
+// search performs a depth-first search of the requirements of the module
+// recording each path found to the target module. The search stops once
+// more than the maximum number of paths have been found.
+func (wf *whyFinder) search(mi *modInfo, path reqPath) {
+	if len(wf.paths) > wf.maxPaths {
+		return
+	}
+
+	if mi == wf.to {
+		wf.paths = append(wf.paths, slices.Clone(path))
+		return
+	}
+
+	wf.onPath[mi.Name] = true
+	defer delete(wf.onPath, mi.Name)
+
+	for _, name := range slices.Sorted(maps.Keys(mi.Reqs)) {
+		if _, ok := wf.canReach[name]; !ok || wf.onPath[name] {
+			continue
+		}
+
+		ri := mi.Reqs[name]
+		wf.search(ri.Mod, append(path, ri))
+	}
+}
+
+// printReqPaths prints the requirement paths from the from module
+func (prog *prog) printReqPaths(w io.Writer, from *modInfo, paths []reqPath) {
+	const (
+		modIndent = "    "
+		reqIndent = modIndent + "    "
+	)
+
+	for i, path := range paths {
+		fmt.Fprintf(w, "\npath %d of %d:\n", i+1, len(paths))
+
+		mi := from
+		for _, ri := range path {
+			fmt.Fprintln(w, modIndent+prog.displayName(mi))
+			fmt.Fprintf(w, "%srequires %s %s (%s) at %s\n",
+				reqIndent,
+				prog.displayName(ri.Mod), ri.Version,
+				ri.kind(), mi.reqLocation(ri))
+
+			mi = ri.Mod
+		}
+
+		fmt.Fprintln(w, modIndent+prog.displayName(mi))
+	}
+}
+
+// displayName returns the module name as it should be shown, with any
+// prefix stripped and a note if the module is external
+func (prog *prog) displayName(mi *modInfo) string {
+	name := strings.TrimPrefix(mi.Name, prog.stripPrefix)
+	if mi.Loc == nil {
+		name += " (external)"
+	}
+
+	return name
+}
+
+// reportWhy prints the chains of requirements between the two modules
+// given in the why parameter. If there are more than the maximum number of
+// paths then only that many are shown. Any errors are returned in the error
+// map.
+func (prog *prog) reportWhy(w io.Writer) *errutil.ErrMap {
+	errMap := errutil.NewErrMap()
+	from, to := prog.mm[prog.whyFrom], prog.mm[prog.whyTo]
+
+	if from == nil || from.Loc == nil {
+		errMap.AddError(paramWhy,
+			fmt.Errorf("module %q is not in the collection of modules",
+				prog.whyFrom))
+	}
+
+	if to == nil {
+		errMap.AddError(paramWhy,
+			fmt.Errorf("module %q is not used by any module", prog.whyTo))
+	}
+
+	if errMap.HasErrors() {
+		return errMap
+	}
+
+	paths := findReqPaths(from, to, prog.whyViaExternal, prog.whyMaxPaths)
+	if len(paths) == 0 {
+		fmt.Fprintf(w, "%s does not require %s\n",
+			prog.displayName(from), prog.displayName(to))
+
+		return errMap
+	}
+
+	if len(paths) > prog.whyMaxPaths {
+		paths = paths[:prog.whyMaxPaths]
+		fmt.Fprintf(w, "%s requires %s through more than %d paths,"+
+			" only the first %d are shown\n",
+			prog.displayName(from), prog.displayName(to),
+			len(paths), len(paths))
+	} else {
+		fmt.Fprintf(w, "%s requires %s through %d path(s)\n",
+			prog.displayName(from), prog.displayName(to), len(paths))
+	}
+
+	prog.printReqPaths(w, from, paths)
+
+	return errMap
+}
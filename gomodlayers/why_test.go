@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReportWhy(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		from, to    string
+		viaExternal bool
+		maxPaths    int
+		expOut      string
+		expErrCount int
+	}{
+		{
+			ID:   testhelper.MkID("multiple paths"),
+			from: "example.com/D",
+			to:   "example.com/B",
+			expOut: "D requires B through 2 path(s)\n" +
+				"\n" +
+				"path 1 of 2:\n" +
+				"    D\n" +
+				"        requires B v1.0.0 (indirect) at test3/go.mod:5\n" +
+				"    B\n" +
+				"\n" +
+				"path 2 of 2:\n" +
+				"    D\n" +
+				"        requires C v1.0.0 (direct) at test3/go.mod:3\n" +
+				"    C\n" +
+				"        requires B v1.0.0 (direct) at test2/go.mod:3\n" +
+				"    B\n",
+		},
+		{
+			ID:   testhelper.MkID("external target"),
+			from: "example.com/C",
+			to:   "example.com/X",
+			expOut: "C requires X (external) through 1 path(s)\n" +
+				"\n" +
+				"path 1 of 1:\n" +
+				"    C\n" +
+				"        requires X (external) v1.0.0 (direct)" +
+				" at test2/go.mod:4\n" +
+				"    X (external)\n",
+		},
+		{
+			ID:       testhelper.MkID("more paths than the maximum"),
+			from:     "example.com/D",
+			to:       "example.com/B",
+			maxPaths: 1,
+			expOut: "D requires B through more than 1 paths," +
+				" only the first 1 are shown\n" +
+				"\n" +
+				"path 1 of 1:\n" +
+				"    D\n" +
+				"        requires B v1.0.0 (indirect) at test3/go.mod:5\n" +
+				"    B\n",
+		},
+		{
+			ID:     testhelper.MkID("no path"),
+			from:   "example.com/A",
+			to:     "example.com/D",
+			expOut: "A does not require D\n",
+		},
+		{
+			ID:          testhelper.MkID("unknown modules"),
+			from:        "example.com/Q",
+			to:          "example.com/R",
+			expErrCount: 2,
+		},
+		{
+			ID:          testhelper.MkID("external from module"),
+			from:        "example.com/X",
+			to:          "example.com/A",
+			expErrCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+		prog.stripPrefix = "example.com/"
+		prog.whyFrom = tc.from
+		prog.whyTo = tc.to
+		prog.whyViaExternal = tc.viaExternal
+
+		if tc.maxPaths > 0 {
+			prog.whyMaxPaths = tc.maxPaths
+		}
+
+		var buf bytes.Buffer
+
+		errMap := prog.reportWhy(&buf)
+
+		errCount, _ := errMap.CountErrors()
+		testhelper.DiffInt(t, tc.IDStr(), "error count",
+			errCount, tc.expErrCount)
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}
+
+func TestReportWhyViaExternal(t *testing.T) {
+	cacheDir := t.TempDir()
+	writeTestFile(t, filepath.Join(cacheDir, "cache", "download",
+		"example.com", "!x", "@v", "v1.0.0.mod"),
+		"module example.com/X\n"+
+			"require example.com/Y v0.5.0\n")
+
+	testCases := []struct {
+		testhelper.ID
+		viaExternal bool
+		expOut      string
+	}{
+		{
+			ID:     testhelper.MkID("not via external modules"),
+			expOut: "C does not require Y (external)\n",
+		},
+		{
+			ID:          testhelper.MkID("via external modules"),
+			viaExternal: true,
+			expOut: "C requires Y (external) through 1 path(s)\n" +
+				"\n" +
+				"path 1 of 1:\n" +
+				"    C\n" +
+				"        requires X (external) v1.0.0 (direct)" +
+				" at test2/go.mod:4\n" +
+				"    X (external)\n" +
+				"        requires Y (external) v0.5.0 (direct)" +
+				" at unknown location\n" +
+				"    Y (external)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		prog := newProg()
+		prog.mm = testModMapABCD(t)
+
+		if errMap := prog.mm.resolveExternal(cacheDir); errMap.HasErrors() {
+			t.Fatal(tc.IDStr(), ": unexpected errors resolving X")
+		}
+
+		prog.stripPrefix = "example.com/"
+		prog.whyFrom = "example.com/C"
+		prog.whyTo = "example.com/Y"
+		prog.whyViaExternal = tc.viaExternal
+
+		var buf bytes.Buffer
+
+		if errMap := prog.reportWhy(&buf); errMap.HasErrors() {
+			t.Fatal(tc.IDStr(), ": unexpected errors")
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "output", buf.String(), tc.expOut)
+	}
+}